@@ -4,6 +4,10 @@
 //   - Queue is the list where the new element is added to the end,
 //     but when an element is taken its taken from the top.
 //     Queue doesn't allow addition of any kind of element. All elements should have the same type.
+//   - TypedQueue is the generic Queue, the element type is checked at compile time.
+//   - ConcurrentQueue is the TypedQueue that is safe to share between the goroutines.
+//   - PriorityQueue is the queue where the element with the highest priority is taken first.
+//   - DelayQueue is the queue where the element becomes visible at the scheduled time.
 //   - Key_value different kinds of maps
 //   - serialize functions to serialize any structure to the bytes and vice versa.
 package data_type
@@ -0,0 +1,218 @@
+package data_type
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQueueClosed is returned by ConcurrentQueue once it was closed.
+var ErrQueueClosed = errors.New("queue is closed")
+
+// ConcurrentQueue is the TypedQueue that could be shared between the goroutines.
+//
+// PushCtx and PopCtx block until the queue has a space or an element.
+// TryPush and TryPop never block.
+//
+// After Close, the pushes fail, while pops return the remaining elements.
+type ConcurrentQueue[T any] struct {
+	mu     sync.Mutex
+	q      *TypedQueue[T]
+	closed bool
+	// changed is closed and replaced every time when the queue state changes.
+	// The blocked goroutines wait on it.
+	changed chan struct{}
+}
+
+// NewConcurrentQueue returns the queue of T elements that could contain
+// maximum QueueCap number of elements.
+func NewConcurrentQueue[T any]() *ConcurrentQueue[T] {
+	return NewConcurrentQueueWithPolicy[T](QueueCap, RejectOverflow)
+}
+
+// NewConcurrentQueueWithPolicy returns the queue of T elements with the given cap.
+// The policy defines what happens when an element is pushed into the full queue.
+// Only the queue with RejectOverflow policy makes PushCtx wait for a space.
+func NewConcurrentQueueWithPolicy[T any](cap uint, policy OverflowPolicy) *ConcurrentQueue[T] {
+	return &ConcurrentQueue[T]{
+		q:       NewTypedQueueWithPolicy[T](cap, policy),
+		changed: make(chan struct{}),
+	}
+}
+
+// notify wakes up all waiting goroutines.
+// The caller must hold the lock.
+func (c *ConcurrentQueue[T]) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *ConcurrentQueue[T]) Len() uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.q.Len()
+}
+
+func (c *ConcurrentQueue[T]) IsEmpty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.q.IsEmpty()
+}
+
+func (c *ConcurrentQueue[T]) IsFull() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.q.IsFull()
+}
+
+// Cap returns the capacity of the queue
+func (c *ConcurrentQueue[T]) Cap() uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.q.Cap()
+}
+
+// SetCap updates the queue size if it's possible.
+// If the new cap is less that current queue size throws an error.
+func (c *ConcurrentQueue[T]) SetCap(newCap uint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.q.SetCap(newCap); err != nil {
+		return fmt.Errorf("q.SetCap: %w", err)
+	}
+	// the waiting pushers may have a space now
+	c.notify()
+
+	return nil
+}
+
+// Policy returns the overflow policy of the queue
+func (c *ConcurrentQueue[T]) Policy() OverflowPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.q.Policy()
+}
+
+// Stats returns the amount of elements lost due to the overflow policy
+func (c *ConcurrentQueue[T]) Stats() QueueStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.q.Stats()
+}
+
+// IsClosed returns true if Close was called
+func (c *ConcurrentQueue[T]) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
+
+// Close the queue and wake up all waiting goroutines.
+// Closing an already closed queue does nothing.
+func (c *ConcurrentQueue[T]) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.notify()
+}
+
+// TryPush adds the element without blocking.
+// Returns an error if the queue is full or closed.
+func (c *ConcurrentQueue[T]) TryPush(item T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrQueueClosed
+	}
+	if err := c.q.Push(item); err != nil {
+		return fmt.Errorf("q.Push: %w", err)
+	}
+	c.notify()
+
+	return nil
+}
+
+// TryPop takes the first element without blocking.
+// If there is no element, then returns false.
+func (c *ConcurrentQueue[T]) TryPop() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.q.Pop()
+	if ok {
+		c.notify()
+	}
+
+	return item, ok
+}
+
+// PushCtx adds the element, waiting for a space if the queue is full.
+// Other than RejectOverflow policies never wait, they are applied immediately.
+// Returns the context error if ctx is done before the element was added,
+// or ErrQueueClosed if the queue is closed.
+func (c *ConcurrentQueue[T]) PushCtx(ctx context.Context, item T) error {
+	for {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return ErrQueueClosed
+		}
+		if !c.q.IsFull() || c.q.Policy() != RejectOverflow {
+			// the queue has a space or drops the elements, so push can't fail
+			_ = c.q.Push(item)
+			c.notify()
+			c.mu.Unlock()
+			return nil
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// PopCtx takes the first element, waiting for it if the queue is empty.
+// Returns the context error if ctx is done before the element was taken,
+// or ErrQueueClosed if the queue is closed and has no elements.
+func (c *ConcurrentQueue[T]) PopCtx(ctx context.Context) (T, error) {
+	for {
+		c.mu.Lock()
+		item, ok := c.q.Pop()
+		if ok {
+			c.notify()
+			c.mu.Unlock()
+			return item, nil
+		}
+		if c.closed {
+			c.mu.Unlock()
+			return item, ErrQueueClosed
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			var empty T
+			return empty, ctx.Err()
+		case <-changed:
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package data_type
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestConcurrentQueueSuite struct {
+	suite.Suite
+	queue *ConcurrentQueue[int]
+}
+
+// SetupTest prepares the empty queue.
+func (suite *TestConcurrentQueueSuite) SetupTest() {
+	queue := NewConcurrentQueue[int]()
+	suite.queue = queue
+
+	suite.Require().True(queue.IsEmpty())
+	suite.Require().False(queue.IsFull())
+	suite.Require().False(queue.IsClosed())
+	suite.Require().Equal(QueueCap, queue.Cap())
+}
+
+func (suite *TestConcurrentQueueSuite) TestTryPushPop() {
+	for i := 0; i < int(QueueCap); i++ {
+		suite.Require().NoError(suite.queue.TryPush(i))
+	}
+	suite.Require().True(suite.queue.IsFull())
+	suite.Require().Error(suite.queue.TryPush(100))
+
+	for i := 0; i < int(QueueCap); i++ {
+		item, ok := suite.queue.TryPop()
+		suite.Require().True(ok)
+		suite.Require().Equal(i, item)
+	}
+
+	_, ok := suite.queue.TryPop()
+	suite.Require().False(ok)
+}
+
+func (suite *TestConcurrentQueueSuite) TestContextCancel() {
+	// nothing to pop
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := suite.queue.PopCtx(ctx)
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+
+	// no space to push
+	suite.Require().NoError(suite.queue.SetCap(1))
+	suite.Require().NoError(suite.queue.TryPush(1))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = suite.queue.PushCtx(ctx, 2)
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+	suite.Require().EqualValues(1, suite.queue.Len())
+}
+
+func (suite *TestConcurrentQueueSuite) TestBlockingPushWaitsForSpace() {
+	suite.Require().NoError(suite.queue.SetCap(1))
+	suite.Require().NoError(suite.queue.TryPush(1))
+
+	pushed := make(chan error)
+	go func() {
+		pushed <- suite.queue.PushCtx(context.Background(), 2)
+	}()
+
+	select {
+	case <-pushed:
+		suite.FailNow("push must wait while the queue is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	item, ok := suite.queue.TryPop()
+	suite.Require().True(ok)
+	suite.Require().Equal(1, item)
+	suite.Require().NoError(<-pushed)
+
+	item, err := suite.queue.PopCtx(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(2, item)
+}
+
+func (suite *TestConcurrentQueueSuite) TestPolicyDoesNotWait() {
+	queue := NewConcurrentQueueWithPolicy[int](1, DropOldest)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	suite.Require().NoError(queue.PushCtx(ctx, 1))
+	suite.Require().NoError(queue.PushCtx(ctx, 2))
+	suite.Require().Equal(QueueStats{DroppedOldest: 1}, queue.Stats())
+
+	item, ok := queue.TryPop()
+	suite.Require().True(ok)
+	suite.Require().Equal(2, item)
+}
+
+func (suite *TestConcurrentQueueSuite) TestCloseWakesWaiters() {
+	waiters := 5
+	errs := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		go func() {
+			_, err := suite.queue.PopCtx(context.Background())
+			errs <- err
+		}()
+	}
+
+	suite.queue.Close()
+	for i := 0; i < waiters; i++ {
+		suite.Require().ErrorIs(<-errs, ErrQueueClosed)
+	}
+
+	// closing twice is allowed
+	suite.queue.Close()
+	suite.Require().ErrorIs(suite.queue.TryPush(1), ErrQueueClosed)
+	suite.Require().ErrorIs(suite.queue.PushCtx(context.Background(), 1), ErrQueueClosed)
+}
+
+func (suite *TestConcurrentQueueSuite) TestCloseDrains() {
+	suite.Require().NoError(suite.queue.TryPush(1))
+	suite.Require().NoError(suite.queue.TryPush(2))
+	suite.queue.Close()
+
+	// the remaining elements are still returned
+	item, err := suite.queue.PopCtx(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, item)
+	item, err = suite.queue.PopCtx(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(2, item)
+
+	_, err = suite.queue.PopCtx(context.Background())
+	suite.Require().ErrorIs(err, ErrQueueClosed)
+}
+
+func (suite *TestConcurrentQueueSuite) TestProducersConsumers() {
+	producers := 4
+	perProducer := 250
+	ctx := context.Background()
+
+	var produced sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		produced.Add(1)
+		go func(p int) {
+			defer produced.Done()
+			for i := 0; i < perProducer; i++ {
+				suite.NoError(suite.queue.PushCtx(ctx, p*perProducer+i))
+			}
+		}(p)
+	}
+
+	var mu sync.Mutex
+	received := make(map[int]bool, producers*perProducer)
+	var consumed sync.WaitGroup
+	for c := 0; c < 3; c++ {
+		consumed.Add(1)
+		go func() {
+			defer consumed.Done()
+			for {
+				item, err := suite.queue.PopCtx(ctx)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				received[item] = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	produced.Wait()
+	suite.queue.Close()
+	consumed.Wait()
+
+	suite.Require().Len(received, producers*perProducer)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestConcurrentQueue(t *testing.T) {
+	suite.Run(t, new(TestConcurrentQueueSuite))
+}
@@ -0,0 +1,226 @@
+package data_type
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+)
+
+// Clock returns the current time.
+// The queues that depend on the time accept it,
+// so the tests could replace it.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the Clock that returns the local time
+var SystemClock Clock = systemClock{}
+
+// delayedItem is the element of DelayQueue with its schedule
+type delayedItem[T any] struct {
+	value     T
+	readyAt   time.Time
+	expiresAt time.Time // zero time means the element never expires
+}
+
+// DelayQueue is the queue where the element becomes visible at the scheduled time.
+// The elements are taken in the order of their scheduled time.
+//
+// The element could have a TTL. If the element was not taken within TTL after
+// its scheduled time, then the queue discards it.
+type DelayQueue[T any] struct {
+	q       *PriorityQueue[delayedItem[T]]
+	clock   Clock
+	expired uint64
+	// The elements with TTL ordered by the expiration time.
+	// It may keep the elements that were already taken from q, they are skipped.
+	expiring *priorityHeap[*PriorityItem[delayedItem[T]]]
+}
+
+// NewDelayQueue returns the queue of T elements that could contain
+// maximum QueueCap number of elements.
+// If the clock is nil, then SystemClock is used.
+func NewDelayQueue[T any](clock Clock) *DelayQueue[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	return &DelayQueue[T]{
+		q: NewPriorityQueueFunc[delayedItem[T]](func(a, b delayedItem[T]) bool {
+			return a.readyAt.Before(b.readyAt)
+		}),
+		clock: clock,
+		expiring: &priorityHeap[*PriorityItem[delayedItem[T]]]{
+			items: make([]*PriorityItem[*PriorityItem[delayedItem[T]]], 0),
+			less: func(a, b *PriorityItem[delayedItem[T]]) bool {
+				return a.value.expiresAt.Before(b.value.expiresAt)
+			},
+		},
+	}
+}
+
+// Len returns the amount of the elements including the ones that are not ready yet.
+// The expired elements are not counted.
+func (q *DelayQueue[T]) Len() uint {
+	q.discardExpired(q.clock.Now())
+	return q.q.Len()
+}
+
+func (q *DelayQueue[T]) IsEmpty() bool {
+	q.discardExpired(q.clock.Now())
+	return q.q.IsEmpty()
+}
+
+func (q *DelayQueue[T]) IsFull() bool {
+	q.discardExpired(q.clock.Now())
+	return q.q.IsFull()
+}
+
+// SetCap updates the queue size if it's possible.
+// If the new cap is less that current queue size throws an error.
+func (q *DelayQueue[T]) SetCap(newCap uint) error {
+	if err := q.q.SetCap(newCap); err != nil {
+		return fmt.Errorf("q.SetCap: %w", err)
+	}
+
+	return nil
+}
+
+// Cap returns the capacity of the queue
+func (q *DelayQueue[T]) Cap() uint {
+	return q.q.Cap()
+}
+
+// Expired returns the amount of the elements discarded due to their TTL
+func (q *DelayQueue[T]) Expired() uint64 {
+	return q.expired
+}
+
+// Push the element that becomes visible at readyAt.
+// The element never expires.
+// Returns an error if the queue is full.
+// The expired elements don't take the place in the queue.
+func (q *DelayQueue[T]) Push(item T, readyAt time.Time) error {
+	q.discardExpired(q.clock.Now())
+	if _, err := q.q.Push(delayedItem[T]{value: item, readyAt: readyAt}, 0); err != nil {
+		return fmt.Errorf("q.Push: %w", err)
+	}
+
+	return nil
+}
+
+// PushWithTTL adds the element that becomes visible at readyAt.
+// If the element is not taken within ttl after readyAt, then it's discarded.
+// Returns an error if the queue is full or ttl is not positive.
+func (q *DelayQueue[T]) PushWithTTL(item T, readyAt time.Time, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("ttl must be positive, given: %s", ttl)
+	}
+
+	q.discardExpired(q.clock.Now())
+	delayed := delayedItem[T]{
+		value:     item,
+		readyAt:   readyAt,
+		expiresAt: readyAt.Add(ttl),
+	}
+	handle, err := q.q.Push(delayed, 0)
+	if err != nil {
+		return fmt.Errorf("q.Push: %w", err)
+	}
+	heap.Push(q.expiring, &PriorityItem[*PriorityItem[delayedItem[T]]]{value: handle})
+	q.compactExpiring()
+
+	return nil
+}
+
+// isExpired returns true if the element's TTL is over
+func (q *DelayQueue[T]) isExpired(item delayedItem[T], now time.Time) bool {
+	return !item.expiresAt.IsZero() && !now.Before(item.expiresAt)
+}
+
+// discardExpired removes the expired elements.
+// The elements are ordered by readyAt, so the expired element could be behind the ones that never expire.
+// Therefore, the elements are expired by the expiring heap.
+func (q *DelayQueue[T]) discardExpired(now time.Time) {
+	for q.expiring.Len() > 0 {
+		item := q.expiring.items[0].value
+		if q.q.contains(item) {
+			if !q.isExpired(item.value, now) {
+				return
+			}
+			q.q.Remove(item)
+			q.expired++
+		}
+		heap.Pop(q.expiring)
+	}
+}
+
+// compactExpiring removes the elements taken from the queue from the expiring heap,
+// when they are the majority, so the heap doesn't grow beyond the queue.
+func (q *DelayQueue[T]) compactExpiring() {
+	if uint(q.expiring.Len()) <= 2*q.q.Len()+QueueCap {
+		return
+	}
+
+	items := q.expiring.items[:0]
+	for _, item := range q.expiring.items {
+		if q.q.contains(item.value) {
+			items = append(items, item)
+		}
+	}
+	for i := len(items); i < len(q.expiring.items); i++ {
+		q.expiring.items[i] = nil
+	}
+	q.expiring.items = items
+	for i, item := range items {
+		item.index = i
+	}
+	heap.Init(q.expiring)
+}
+
+// Peek returns the earliest element which time has arrived, without removing it from the queue.
+// If there is no ready element, then returns false.
+func (q *DelayQueue[T]) Peek() (T, bool) {
+	now := q.clock.Now()
+	q.discardExpired(now)
+
+	item, ok := q.q.Peek()
+	if !ok || item.readyAt.After(now) {
+		var empty T
+		return empty, false
+	}
+
+	return item.value, true
+}
+
+// Pop takes the earliest element which time has arrived.
+// If there is no ready element, then returns false.
+func (q *DelayQueue[T]) Pop() (T, bool) {
+	value, ok := q.Peek()
+	if !ok {
+		return value, false
+	}
+
+	q.q.Pop()
+
+	return value, true
+}
+
+// NextReadyAt returns the time when the earliest element becomes visible.
+// If the queue is empty, then returns false.
+func (q *DelayQueue[T]) NextReadyAt() (time.Time, bool) {
+	q.discardExpired(q.clock.Now())
+
+	item, ok := q.q.Peek()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return item.readyAt, true
+}
@@ -0,0 +1,162 @@
+package data_type
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// testClock is the Clock that moves only when the test wants.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestDelayQueueSuite struct {
+	suite.Suite
+	clock *testClock
+	queue *DelayQueue[string]
+}
+
+// SetupTest prepares the empty queue with the controlled clock.
+func (suite *TestDelayQueueSuite) SetupTest() {
+	suite.clock = &testClock{now: time.Unix(1_000_000, 0)}
+	queue := NewDelayQueue[string](suite.clock)
+	suite.queue = queue
+
+	suite.Require().True(queue.IsEmpty())
+	suite.Require().False(queue.IsFull())
+	suite.Require().Equal(QueueCap, queue.Cap())
+
+	_, ok := queue.Pop()
+	suite.Require().False(ok)
+	_, ok = queue.NextReadyAt()
+	suite.Require().False(ok)
+}
+
+func (suite *TestDelayQueueSuite) TestDelay() {
+	now := suite.clock.Now()
+	suite.Require().NoError(suite.queue.Push("later", now.Add(2*time.Second)))
+	suite.Require().NoError(suite.queue.Push("soon", now.Add(time.Second)))
+	suite.Require().NoError(suite.queue.Push("now", now))
+	suite.Require().EqualValues(3, suite.queue.Len())
+
+	value, ok := suite.queue.Pop()
+	suite.Require().True(ok)
+	suite.Require().Equal("now", value)
+
+	// nothing is ready yet
+	_, ok = suite.queue.Pop()
+	suite.Require().False(ok)
+	readyAt, ok := suite.queue.NextReadyAt()
+	suite.Require().True(ok)
+	suite.Require().Equal(now.Add(time.Second), readyAt)
+
+	suite.clock.Add(5 * time.Second)
+	value, ok = suite.queue.Peek()
+	suite.Require().True(ok)
+	suite.Require().Equal("soon", value)
+	value, ok = suite.queue.Pop()
+	suite.Require().True(ok)
+	suite.Require().Equal("soon", value)
+	value, ok = suite.queue.Pop()
+	suite.Require().True(ok)
+	suite.Require().Equal("later", value)
+
+	suite.Require().True(suite.queue.IsEmpty())
+}
+
+func (suite *TestDelayQueueSuite) TestTTL() {
+	now := suite.clock.Now()
+	suite.Require().Error(suite.queue.PushWithTTL("invalid", now, 0))
+
+	suite.Require().NoError(suite.queue.PushWithTTL("stale", now, time.Second))
+	suite.Require().NoError(suite.queue.PushWithTTL("fresh", now.Add(time.Second), time.Minute))
+	suite.Require().NoError(suite.queue.Push("forever", now.Add(2*time.Second)))
+
+	suite.clock.Add(time.Hour)
+
+	// the stale element is discarded
+	value, ok := suite.queue.Pop()
+	suite.Require().True(ok)
+	suite.Require().Equal("forever", value)
+	suite.Require().EqualValues(2, suite.queue.Expired())
+	suite.Require().True(suite.queue.IsEmpty())
+}
+
+func (suite *TestDelayQueueSuite) TestCap() {
+	suite.Require().NoError(suite.queue.SetCap(1))
+	suite.Require().NoError(suite.queue.Push("a", suite.clock.Now()))
+	suite.Require().True(suite.queue.IsFull())
+	suite.Require().Error(suite.queue.Push("b", suite.clock.Now()))
+	suite.Require().Error(suite.queue.SetCap(0))
+}
+
+func (suite *TestDelayQueueSuite) TestCapExpired() {
+	now := suite.clock.Now()
+	suite.Require().NoError(suite.queue.SetCap(2))
+	suite.Require().NoError(suite.queue.Push("forever", now))
+	suite.Require().NoError(suite.queue.PushWithTTL("stale", now.Add(time.Second), time.Second))
+	suite.Require().True(suite.queue.IsFull())
+
+	// the expired element behind the one that never expires frees its place
+	suite.clock.Add(time.Hour)
+	suite.Require().False(suite.queue.IsFull())
+	suite.Require().EqualValues(1, suite.queue.Len())
+	suite.Require().EqualValues(1, suite.queue.Expired())
+
+	suite.Require().NoError(suite.queue.PushWithTTL("short", now, time.Minute))
+	suite.Require().NoError(suite.queue.Push("new", suite.clock.Now()))
+	suite.Require().EqualValues(2, suite.queue.Expired())
+	suite.Require().True(suite.queue.IsFull())
+}
+
+func (suite *TestDelayQueueSuite) TestExpiring() {
+	now := suite.clock.Now()
+	suite.Require().NoError(suite.queue.SetCap(1000))
+	for i := 0; i < 100; i++ {
+		suite.Require().NoError(suite.queue.PushWithTTL("item", now, time.Hour))
+		_, ok := suite.queue.Pop()
+		suite.Require().True(ok)
+	}
+
+	// the taken elements don't stay in the expiring heap
+	suite.Require().LessOrEqual(suite.queue.expiring.Len(), int(QueueCap)+1)
+
+	// the size checks don't allocate
+	suite.Require().NoError(suite.queue.PushWithTTL("item", now, time.Hour))
+	allocs := testing.AllocsPerRun(10, func() {
+		suite.queue.Len()
+		suite.queue.IsFull()
+	})
+	suite.Require().Zero(allocs)
+}
+
+func (suite *TestDelayQueueSuite) TestSystemClock() {
+	queue := NewDelayQueue[int](nil)
+	suite.Require().NoError(queue.Push(1, time.Now().Add(-time.Second)))
+	suite.Require().NoError(queue.Push(2, time.Now().Add(time.Hour)))
+
+	value, ok := queue.Pop()
+	suite.Require().True(ok)
+	suite.Require().Equal(1, value)
+	_, ok = queue.Pop()
+	suite.Require().False(ok)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestDelayQueue(t *testing.T) {
+	suite.Run(t, new(TestDelayQueueSuite))
+}
@@ -0,0 +1,227 @@
+package key_value
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// The binary encodings (MessagePack and CBOR) work with the following go types only:
+// bool, string, []byte, uint64, int64, float64, *big.Int, []interface{} and map[string]interface{}.
+// The values of KeyValue are converted into these types by binaryValue,
+// and the decoders return these types.
+//
+// The unsigned go integers are kept as uint64, the signed ones as int64.
+// The json.Number is converted into the smallest type that keeps its value:
+// uint64 for the natural numbers, int64 for the negative ones,
+// *big.Int if it doesn't fit into 64 bits, and float64 for the fractions.
+
+// maxBinaryDepth is the maximum nesting of the maps and lists in the binary data.
+const maxBinaryDepth = 1000
+
+// binaryValue converts the value of KeyValue into the type supported by the binary encodings
+func binaryValue(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("value is %w", ErrNilValue)
+	case bool, string, []byte, uint64, int64, float64:
+		return typed, nil
+	case json.Number:
+		return jsonNumberValue(typed)
+	case *big.Int:
+		if typed == nil {
+			return nil, fmt.Errorf("value is %w", ErrNilValue)
+		}
+		return typed, nil
+	case time.Time:
+		// same as json does
+		return typed.Format(time.RFC3339Nano), nil
+	case KeyValue:
+		return binaryMap(typed)
+	case map[string]interface{}:
+		return binaryMap(typed)
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Bool:
+		return reflected.Bool(), nil
+	case reflect.String:
+		return reflected.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflected.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), nil
+	case reflect.Pointer:
+		if reflected.IsNil() {
+			return nil, fmt.Errorf("value is %w", ErrNilValue)
+		}
+	case reflect.Slice:
+		if reflected.Type().Elem().Kind() == reflect.Uint8 {
+			return reflected.Bytes(), nil
+		}
+		list, _ := toList(value)
+		converted := make([]interface{}, len(list))
+		for i, element := range list {
+			convertedElement, err := binaryValue(element)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			converted[i] = convertedElement
+		}
+		return converted, nil
+	}
+
+	// the structs and other types are converted the way json sees them
+	return jsonValue(value)
+}
+
+// binaryMap converts the values of the map
+func binaryMap(kv KeyValue) (map[string]interface{}, error) {
+	converted := make(map[string]interface{}, len(kv))
+	for key, value := range kv {
+		convertedValue, err := binaryValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		converted[key] = convertedValue
+	}
+
+	return converted, nil
+}
+
+// jsonNumberValue returns the json number as uint64, int64, *big.Int or float64
+func jsonNumberValue(number json.Number) (interface{}, error) {
+	if integer, ok := new(big.Int).SetString(string(number), 10); ok {
+		if integer.IsUint64() {
+			return integer.Uint64(), nil
+		}
+		if integer.IsInt64() {
+			return integer.Int64(), nil
+		}
+		return integer, nil
+	}
+
+	f, err := strconv.ParseFloat(string(number), 64)
+	if err != nil {
+		return nil, fmt.Errorf("json number '%s': %w", number, err)
+	}
+
+	return f, nil
+}
+
+// jsonValue converts the value through json
+func jsonValue(value interface{}) (interface{}, error) {
+	kv, err := NewFromInterface(map[string]interface{}{"value": value})
+	if err != nil {
+		return nil, fmt.Errorf("can not convert %T: %w", value, err)
+	}
+
+	return binaryValue(kv["value"])
+}
+
+// binaryReader reads the binary data.
+// It never returns more bytes than the data has,
+// so the invalid lengths don't allocate the memory.
+type binaryReader struct {
+	data   []byte
+	offset int
+	depth  int
+}
+
+// remaining returns the amount of the unread bytes
+func (r *binaryReader) remaining() uint64 {
+	return uint64(len(r.data) - r.offset)
+}
+
+func (r *binaryReader) readByte() (byte, error) {
+	if r.remaining() < 1 {
+		return 0, fmt.Errorf("unexpected end of data at %d", r.offset)
+	}
+	b := r.data[r.offset]
+	r.offset++
+
+	return b, nil
+}
+
+func (r *binaryReader) peekByte() (byte, error) {
+	if r.remaining() < 1 {
+		return 0, fmt.Errorf("unexpected end of data at %d", r.offset)
+	}
+
+	return r.data[r.offset], nil
+}
+
+// readBytes returns the next n bytes
+func (r *binaryReader) readBytes(n uint64) ([]byte, error) {
+	if r.remaining() < n {
+		return nil, fmt.Errorf("length %d at %d exceeds the data", n, r.offset)
+	}
+	b := r.data[r.offset : r.offset+int(n)]
+	r.offset += int(n)
+
+	return b, nil
+}
+
+// readUint reads the big-endian unsigned integer of the given size in bytes
+func (r *binaryReader) readUint(size int) (uint64, error) {
+	b, err := r.readBytes(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+
+	var value uint64
+	for _, part := range b {
+		value = value<<8 | uint64(part)
+	}
+
+	return value, nil
+}
+
+// checkLength returns an error if the data can not have the amount of the elements.
+// Each element takes at least one byte.
+func (r *binaryReader) checkLength(n uint64) error {
+	if n > r.remaining() {
+		return fmt.Errorf("%d elements at %d exceed the data", n, r.offset)
+	}
+	return nil
+}
+
+// enter increases the nesting depth
+func (r *binaryReader) enter() error {
+	r.depth++
+	if r.depth > maxBinaryDepth {
+		return fmt.Errorf("nesting exceeds %d levels", maxBinaryDepth)
+	}
+	return nil
+}
+
+func (r *binaryReader) leave() {
+	r.depth--
+}
+
+// binaryToKeyValue returns the decoded top level value as KeyValue
+func binaryToKeyValue(value interface{}, r *binaryReader) (KeyValue, error) {
+	if r.remaining() > 0 {
+		return nil, fmt.Errorf("%d bytes left after the value", r.remaining())
+	}
+	decoded, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("top level value is %T, not a map", value)
+	}
+
+	return decoded, nil
+}
+
+// putUint appends the big-endian unsigned integer of the given size in bytes
+func putUint(buffer []byte, value uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		buffer = append(buffer, byte(value>>(8*uint(i))))
+	}
+	return buffer
+}
@@ -0,0 +1,463 @@
+package key_value
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// BindTag is the struct tag with the validation rules of the field:
+//
+//	Amount uint64 `json:"amount" kv:"required,min=1,max=100"`
+//
+// The rules are:
+//   - required - the key must exist.
+//   - min=N, max=N - the range of the number,
+//     or the length of the string, slice or map.
+//     For time.Duration fields, the limits could be written as the duration: min=1s.
+const BindTag = "kv"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+	textType     = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// bindRules are the parsed rules of the BindTag
+type bindRules struct {
+	required bool
+	min      string
+	max      string
+}
+
+// binder collects the errors of all fields
+type binder struct {
+	validation
+}
+
+// Bind sets the struct fields pointed by dst from the kv directly, without the json serialization.
+// The fields are matched by the json tags, same as json.Unmarshal does.
+//
+// Besides the basic types, it supports *big.Int,
+// time.Time (RFC 3339 string or unix seconds),
+// time.Duration (string like "1m30s" or nanoseconds)
+// and the embedded structs.
+// The fields are validated by the rules in the BindTag.
+//
+// Returns *ValidationError listing all failed fields.
+// The fields that passed are set even if the other fields failed.
+func Bind(kv KeyValue, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("dst must be a non-nil pointer, given %T", dst)
+	}
+	if value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dst must point to a struct, given %T", dst)
+	}
+
+	b := &binder{}
+	b.bindStruct("", kv, value.Elem())
+	if len(b.errors) > 0 {
+		return &ValidationError{Errors: b.errors}
+	}
+
+	return nil
+}
+
+// fieldName returns the key of the field by the json tag.
+// If the field has to be skipped, then it returns false.
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if len(name) == 0 {
+		return field.Name, true
+	}
+
+	return name, true
+}
+
+// parseRules returns the rules of the BindTag
+func parseRules(tag string) (bindRules, error) {
+	rules := bindRules{}
+	if len(tag) == 0 {
+		return rules, nil
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			rules.required = true
+		case "min":
+			rules.min = argument
+		case "max":
+			rules.max = argument
+		default:
+			return rules, fmt.Errorf("unknown rule '%s' in tag '%s'", rule, tag)
+		}
+		if (name == "min" || name == "max") && len(argument) == 0 {
+			return rules, fmt.Errorf("rule '%s' has no limit in tag '%s'", name, tag)
+		}
+	}
+
+	return rules, nil
+}
+
+// lookup returns the value by the key.
+// Same as json.Unmarshal, if the key doesn't exist, then it's matched case-insensitively.
+func lookup(kv KeyValue, name string) (interface{}, bool) {
+	if value, ok := kv[name]; ok {
+		return value, true
+	}
+	for key, value := range kv {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// bindStruct sets the fields of the struct value from the kv
+func (b *binder) bindStruct(path string, kv KeyValue, value reflect.Value) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+
+		// the fields of the embedded struct are bound as the fields of the parent
+		if field.Anonymous && len(field.Tag.Get("json")) == 0 {
+			fieldValue := value.Field(i)
+			if field.Type.Kind() == reflect.Struct {
+				b.bindStruct(path, kv, fieldValue)
+				continue
+			}
+			if field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct {
+				if !field.IsExported() {
+					continue
+				}
+				if fieldValue.IsNil() {
+					fieldValue.Set(reflect.New(field.Type.Elem()))
+				}
+				b.bindStruct(path, kv, fieldValue.Elem())
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := joinPath(path, name)
+		rules, err := parseRules(field.Tag.Get(BindTag))
+		if err != nil {
+			b.fail(fieldPath, "%v", err)
+			continue
+		}
+
+		raw, ok := lookup(kv, name)
+		if !ok {
+			if rules.required {
+				b.fail(fieldPath, "required")
+			}
+			continue
+		}
+
+		if b.set(fieldPath, raw, value.Field(i)) {
+			b.check(fieldPath, rules, value.Field(i))
+		}
+	}
+}
+
+// set converts the raw value into the type of the target.
+// Returns false if the value could not be converted.
+func (b *binder) set(path string, raw interface{}, target reflect.Value) bool {
+	if raw == nil {
+		b.fail(path, "nil value")
+		return false
+	}
+
+	if target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return b.set(path, raw, target.Elem())
+	}
+
+	switch target.Type() {
+	case durationType:
+		return b.setDuration(path, raw, target)
+	case timeType:
+		if number, ok := canonicalNumber(raw); ok {
+			seconds, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				b.fail(path, "unix time %s is not an integer", number)
+				return false
+			}
+			target.Set(reflect.ValueOf(time.Unix(seconds, 0).UTC()))
+			return true
+		}
+	case bigIntType:
+		if number, ok := canonicalNumber(raw); ok {
+			bigInt, ok := new(big.Int).SetString(number, 10)
+			if !ok {
+				b.fail(path, "%s is not an integer", number)
+				return false
+			}
+			target.Set(reflect.ValueOf(*bigInt))
+			return true
+		}
+	}
+
+	if str, ok := raw.(string); ok && reflect.PointerTo(target.Type()).Implements(textType) {
+		if err := target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			b.fail(path, "can not parse '%s' as %s: %v", str, target.Type(), err)
+			return false
+		}
+		return true
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		value, ok := raw.(bool)
+		if !ok {
+			b.fail(path, "expected bool, got %T", raw)
+			return false
+		}
+		target.SetBool(value)
+	case reflect.String:
+		value, ok := raw.(string)
+		if !ok {
+			b.fail(path, "expected string, got %T", raw)
+			return false
+		}
+		target.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := b.number(path, raw)
+		if !ok {
+			return false
+		}
+		value, err := strconv.ParseInt(number, 10, target.Type().Bits())
+		if err != nil {
+			b.fail(path, "%s is not %s", number, target.Type())
+			return false
+		}
+		target.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		number, ok := b.number(path, raw)
+		if !ok {
+			return false
+		}
+		value, err := strconv.ParseUint(number, 10, target.Type().Bits())
+		if err != nil {
+			b.fail(path, "%s is not %s", number, target.Type())
+			return false
+		}
+		target.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		number, ok := b.number(path, raw)
+		if !ok {
+			return false
+		}
+		value, err := strconv.ParseFloat(number, target.Type().Bits())
+		if err != nil {
+			b.fail(path, "%s is not %s", number, target.Type())
+			return false
+		}
+		target.SetFloat(value)
+	case reflect.Interface:
+		if target.NumMethod() != 0 {
+			b.fail(path, "can not bind into %s", target.Type())
+			return false
+		}
+		target.Set(reflect.ValueOf(deepCopy(raw)))
+	case reflect.Struct:
+		nested, ok := toKeyValue(raw)
+		if !ok {
+			b.fail(path, "expected kv-value, got %T", raw)
+			return false
+		}
+		errorsBefore := len(b.errors)
+		b.bindStruct(path, nested, target)
+		return len(b.errors) == errorsBefore
+	case reflect.Map:
+		return b.setMap(path, raw, target)
+	case reflect.Slice:
+		return b.setSlice(path, raw, target)
+	default:
+		b.fail(path, "can not bind into %s", target.Type())
+		return false
+	}
+
+	return true
+}
+
+// number returns the raw number in the decimal format
+func (b *binder) number(path string, raw interface{}) (string, bool) {
+	number, ok := canonicalNumber(raw)
+	if !ok {
+		b.fail(path, "expected number, got %T", raw)
+	}
+
+	return number, ok
+}
+
+// setDuration sets the duration from the string like "1m30s" or from the nanoseconds
+func (b *binder) setDuration(path string, raw interface{}, target reflect.Value) bool {
+	if str, ok := raw.(string); ok {
+		duration, err := time.ParseDuration(str)
+		if err != nil {
+			b.fail(path, "time.ParseDuration: %v", err)
+			return false
+		}
+		target.SetInt(int64(duration))
+		return true
+	}
+
+	number, ok := b.number(path, raw)
+	if !ok {
+		return false
+	}
+	nanoseconds, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		b.fail(path, "%s is not a duration in nanoseconds", number)
+		return false
+	}
+	target.SetInt(nanoseconds)
+
+	return true
+}
+
+// setMap sets the map with the string keys from the kv
+func (b *binder) setMap(path string, raw interface{}, target reflect.Value) bool {
+	if target.Type().Key().Kind() != reflect.String {
+		b.fail(path, "can not bind into %s, the key must be a string", target.Type())
+		return false
+	}
+	nested, ok := toKeyValue(raw)
+	if !ok {
+		b.fail(path, "expected kv-value, got %T", raw)
+		return false
+	}
+
+	keys := make([]string, 0, len(nested))
+	for key := range nested {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := reflect.MakeMapWithSize(target.Type(), len(nested))
+	valid := true
+	for _, key := range keys {
+		element := reflect.New(target.Type().Elem()).Elem()
+		if !b.set(joinPath(path, key), nested[key], element) {
+			valid = false
+			continue
+		}
+		result.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), element)
+	}
+	target.Set(result)
+
+	return valid
+}
+
+// setSlice sets the slice from the list.
+// Same as json.Unmarshal, the bytes are decoded from the base64 string.
+func (b *binder) setSlice(path string, raw interface{}, target reflect.Value) bool {
+	if str, ok := raw.(string); ok && target.Type().Elem().Kind() == reflect.Uint8 {
+		bytes, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			b.fail(path, "base64: %v", err)
+			return false
+		}
+		target.SetBytes(bytes)
+		return true
+	}
+
+	list, ok := toList(raw)
+	if !ok {
+		b.fail(path, "expected list, got %T", raw)
+		return false
+	}
+
+	result := reflect.MakeSlice(target.Type(), len(list), len(list))
+	valid := true
+	for i, element := range list {
+		if !b.set(joinPath(path, strconv.Itoa(i)), element, result.Index(i)) {
+			valid = false
+		}
+	}
+	target.Set(result)
+
+	return valid
+}
+
+// check validates the bound value by the min and max rules
+func (b *binder) check(path string, rules bindRules, value reflect.Value) {
+	if len(rules.min) == 0 && len(rules.max) == 0 {
+		return
+	}
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	var measured *big.Float
+	switch value.Kind() {
+	case reflect.String:
+		measured = new(big.Float).SetInt64(int64(utf8.RuneCountInString(value.String())))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		measured = new(big.Float).SetInt64(int64(value.Len()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		measured = new(big.Float).SetInt64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		measured = new(big.Float).SetUint64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		measured = big.NewFloat(value.Float())
+	case reflect.Struct:
+		if value.Type() == bigIntType {
+			bigInt := value.Interface().(big.Int)
+			measured = new(big.Float).SetInt(&bigInt)
+		}
+	}
+	if measured == nil {
+		b.fail(path, "min and max rules are not supported by %s", value.Type())
+		return
+	}
+
+	for _, limit := range []struct {
+		rule string
+		sign int
+		name string
+	}{{rules.min, -1, "minimum"}, {rules.max, 1, "maximum"}} {
+		if len(limit.rule) == 0 {
+			continue
+		}
+		parsed, ok := new(big.Float).SetString(limit.rule)
+		if value.Type() == durationType {
+			if duration, err := time.ParseDuration(limit.rule); err == nil {
+				parsed, ok = new(big.Float).SetInt64(int64(duration)), true
+			}
+		}
+		if !ok {
+			b.fail(path, "invalid %s '%s'", limit.name, limit.rule)
+			continue
+		}
+		if measured.Cmp(parsed) == limit.sign {
+			b.fail(path, "%s is beyond the %s %s", measured.String(), limit.name, limit.rule)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package key_value
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type bindBase struct {
+	ID string `json:"id" kv:"required,min=3"`
+}
+
+type bindItem struct {
+	Qty uint32 `json:"qty" kv:"min=1"`
+}
+
+type bindOrder struct {
+	bindBase
+	Amount    uint64            `json:"amount" kv:"required,min=1,max=100"`
+	Delta     int64             `json:"delta"`
+	Price     float64           `json:"price"`
+	Supply    *big.Int          `json:"supply"`
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	Timeout   time.Duration     `json:"timeout" kv:"max=1m"`
+	Paid      bool              `json:"paid"`
+	Note      *string           `json:"note"`
+	Tags      []string          `json:"tags" kv:"max=2"`
+	Items     []bindItem        `json:"items"`
+	Labels    map[string]string `json:"labels"`
+	Extra     interface{}       `json:"extra"`
+	Raw       []byte            `json:"raw"`
+	Ignored   string            `json:"-"`
+	Default   string
+}
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestBindSuite struct {
+	suite.Suite
+}
+
+func (suite *TestBindSuite) TestBind() {
+	kv, err := NewFromString(`{"id":"ord-1","amount":5,"delta":-3,"price":1.5,
+		"supply":123456789012345678901234567890,"created_at":"2023-05-01T10:00:00Z","expires_at":1682935200,
+		"timeout":"30s","paid":true,"note":"fragile","tags":["a","b"],"items":[{"qty":1},{"qty":2}],
+		"labels":{"env":"prod"},"extra":{"nested":[1]},"raw":"aGVsbG8=","Ignored":"x","default":"y"}`)
+	suite.Require().NoError(err)
+
+	var order bindOrder
+	suite.Require().NoError(Bind(kv, &order))
+
+	supply, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	suite.Require().Equal("ord-1", order.ID)
+	suite.Require().Equal(uint64(5), order.Amount)
+	suite.Require().Equal(int64(-3), order.Delta)
+	suite.Require().Equal(1.5, order.Price)
+	suite.Require().Zero(supply.Cmp(order.Supply))
+	suite.Require().Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), order.CreatedAt.UTC())
+	suite.Require().Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), order.ExpiresAt)
+	suite.Require().Equal(30*time.Second, order.Timeout)
+	suite.Require().True(order.Paid)
+	suite.Require().Equal("fragile", *order.Note)
+	suite.Require().Equal([]string{"a", "b"}, order.Tags)
+	suite.Require().Equal([]bindItem{{Qty: 1}, {Qty: 2}}, order.Items)
+	suite.Require().Equal(map[string]string{"env": "prod"}, order.Labels)
+	suite.Require().NotNil(order.Extra)
+	suite.Require().Equal([]byte("hello"), order.Raw)
+	suite.Require().Empty(order.Ignored)
+	// without the json tag, the field name is matched case-insensitively
+	suite.Require().Equal("y", order.Default)
+
+	// the big number is set without the precision loss
+	kv = KeyValue{"id": "ord-2", "amount": uint64(1), "supply": "987654321987654321987654321", "timeout": 1000}
+	order = bindOrder{}
+	suite.Require().NoError(Bind(kv, &order))
+	suite.Require().Equal("987654321987654321987654321", order.Supply.String())
+	suite.Require().Equal(time.Microsecond, order.Timeout)
+}
+
+func (suite *TestBindSuite) TestErrors() {
+	kv, err := NewFromString(`{"id":"o","amount":500,"delta":1.5,"price":"cheap","timeout":"2m",
+		"paid":1,"tags":["a","b","c"],"items":[{"qty":0},{"qty":-1}],"created_at":"yesterday"}`)
+	suite.Require().NoError(err)
+
+	var order bindOrder
+	err = Bind(kv, &order)
+	suite.Require().Error(err)
+
+	var validationErr *ValidationError
+	suite.Require().True(errors.As(err, &validationErr))
+	paths := make([]string, len(validationErr.Errors))
+	for i, fieldErr := range validationErr.Errors {
+		paths[i] = fieldErr.Path
+	}
+	suite.Require().ElementsMatch([]string{
+		"id",
+		"amount",
+		"delta",
+		"price",
+		"created_at",
+		"timeout",
+		"paid",
+		"tags",
+		"items.0.qty",
+		"items.1.qty",
+	}, paths)
+
+	// required fields
+	err = Bind(KeyValue{}, &order)
+	suite.Require().True(errors.As(err, &validationErr))
+	suite.Require().Equal([]FieldError{
+		{Path: "id", Message: "required"},
+		{Path: "amount", Message: "required"},
+	}, validationErr.Errors)
+
+	// only the pointer to the struct is accepted
+	suite.Require().Error(Bind(kv, order))
+	suite.Require().Error(Bind(kv, (*bindOrder)(nil)))
+	str := ""
+	suite.Require().Error(Bind(kv, &str))
+
+	// invalid tag is reported
+	var invalid struct {
+		Value uint64 `json:"value" kv:"between=1"`
+	}
+	suite.Require().Error(Bind(KeyValue{"value": uint64(1)}, &invalid))
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestBind(t *testing.T) {
+	suite.Run(t, new(TestBindSuite))
+}
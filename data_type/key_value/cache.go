@@ -0,0 +1,285 @@
+package key_value
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahmetson/datatype-lib/data_type"
+)
+
+// EvictionPolicy defines which element the Cache removes when it's full.
+type EvictionPolicy uint8
+
+const (
+	// LRU removes the least recently used element
+	LRU EvictionPolicy = iota
+	// LFU removes the least frequently used element.
+	// Among the elements with the same frequency, the least recently used is removed.
+	LFU
+)
+
+// EvictionReason explains why the element was removed from the Cache
+type EvictionReason uint8
+
+const (
+	// EvictedByCap means the element was removed to make a space for the new one
+	EvictedByCap EvictionReason = iota
+	// EvictedByTTL means the element's TTL is over
+	EvictedByTTL
+)
+
+// EvictFunc is called when the Cache removes the element by itself.
+type EvictFunc func(key interface{}, value interface{}, reason EvictionReason)
+
+// CacheStats counts the cache usage
+type CacheStats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64 // The elements removed by the EvictedByCap reason
+	Expirations uint64 // The elements removed by the EvictedByTTL reason
+}
+
+// Cache is the List that removes the elements instead of failing when the cap is reached.
+// The key and value types follow the List rules:
+// all keys must have the same type, all values must have the same type.
+//
+// The elements are kept in the List from the least recently used to the most recently used.
+type Cache struct {
+	list      *List
+	policy    EvictionPolicy
+	frequency map[interface{}]uint64
+	expiresAt map[interface{}]time.Time
+	ttl       time.Duration
+	clock     data_type.Clock
+	onEvict   EvictFunc
+	stats     CacheStats
+}
+
+// NewCache returns the cache that could keep maximum DefaultCap elements.
+// If the clock is nil, then data_type.SystemClock is used.
+func NewCache(policy EvictionPolicy, clock data_type.Clock) *Cache {
+	if clock == nil {
+		clock = data_type.SystemClock
+	}
+
+	return &Cache{
+		list:      NewList(),
+		policy:    policy,
+		frequency: map[interface{}]uint64{},
+		expiresAt: map[interface{}]time.Time{},
+		clock:     clock,
+	}
+}
+
+func (c *Cache) Len() uint {
+	return c.list.Len()
+}
+
+func (c *Cache) IsEmpty() bool {
+	return c.list.IsEmpty()
+}
+
+// SetCap updates the capacity of the cache.
+// If the cache has more elements than newCap, it throws an error.
+func (c *Cache) SetCap(newCap uint) error {
+	if err := c.list.SetCap(newCap); err != nil {
+		return fmt.Errorf("list.SetCap: %w", err)
+	}
+
+	return nil
+}
+
+// Cap returns the capacity of the cache
+func (c *Cache) Cap() uint {
+	return c.list.Cap()
+}
+
+// Policy returns the eviction policy of the cache
+func (c *Cache) Policy() EvictionPolicy {
+	return c.policy
+}
+
+// SetTTL sets the default TTL of the elements added by Set.
+// Zero ttl means the elements never expire.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	c.ttl = ttl
+}
+
+// OnEvict sets the function called when the cache removes the element by itself
+func (c *Cache) OnEvict(onEvict EvictFunc) {
+	c.onEvict = onEvict
+}
+
+// Stats returns the hits, misses and removals of the cache
+func (c *Cache) Stats() CacheStats {
+	return c.stats
+}
+
+// Set adds the element or replaces the existing one.
+// The element expires after the default TTL.
+// If the cache is full, then the element chosen by the policy is evicted.
+func (c *Cache) Set(key interface{}, value interface{}) error {
+	return c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds the element or replaces the existing one.
+// The element expires after ttl. Zero ttl means the element never expires.
+// If the cache is full, then the element chosen by the policy is evicted.
+func (c *Cache) SetWithTTL(key interface{}, value interface{}, ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("ttl can not be negative, given: %s", ttl)
+	}
+	if err := c.list.validate(key, value); err != nil {
+		return fmt.Errorf("list.validate: %w", err)
+	}
+
+	if c.list.Exist(key) {
+		// replacing keeps the frequency, but the element becomes the most recent
+		c.list.remove(key)
+	} else if c.list.IsFull() {
+		if err := c.evict(); err != nil {
+			return fmt.Errorf("evict: %w", err)
+		}
+	}
+
+	if err := c.list.Add(key, value); err != nil {
+		return fmt.Errorf("list.Add: %w", err)
+	}
+	if ttl > 0 {
+		c.expiresAt[key] = c.clock.Now().Add(ttl)
+	} else {
+		delete(c.expiresAt, key)
+	}
+
+	return nil
+}
+
+// Get returns the element and marks it as the most recently used.
+// Returns an error if the element doesn't exist, or it has expired.
+func (c *Cache) Get(key interface{}) (interface{}, error) {
+	if c.deleteIfExpired(key) {
+		c.stats.Misses++
+		return nil, fmt.Errorf("the element expired")
+	}
+
+	value, err := c.list.Get(key)
+	if err != nil {
+		c.stats.Misses++
+		return nil, fmt.Errorf("list.Get: %w", err)
+	}
+
+	c.stats.Hits++
+	c.frequency[key]++
+	// the element exists, therefore, it can't fail
+	_ = c.list.MoveToBack(key)
+
+	return value, nil
+}
+
+// Exist returns true if the element is in the cache and not expired.
+// It doesn't change the usage of the element.
+func (c *Cache) Exist(key interface{}) bool {
+	if !c.list.Exist(key) {
+		return false
+	}
+
+	return !c.isExpired(key, c.clock.Now())
+}
+
+// Remove the element from the cache and return it.
+// The eviction function is not called.
+func (c *Cache) Remove(key interface{}) (interface{}, error) {
+	value, err := c.list.Take(key)
+	if err != nil {
+		return nil, fmt.Errorf("list.Take: %w", err)
+	}
+	delete(c.frequency, key)
+	delete(c.expiresAt, key)
+
+	return value, nil
+}
+
+// DeleteExpired removes all expired elements.
+// Returns the amount of the removed elements.
+func (c *Cache) DeleteExpired() uint {
+	now := c.clock.Now()
+	expired := make([]interface{}, 0)
+	for key := range c.expiresAt {
+		if c.isExpired(key, now) {
+			expired = append(expired, key)
+		}
+	}
+
+	for _, key := range expired {
+		c.delete(key, EvictedByTTL)
+	}
+
+	return uint(len(expired))
+}
+
+// isExpired returns true if the element's TTL is over
+func (c *Cache) isExpired(key interface{}, now time.Time) bool {
+	expiresAt, ok := c.expiresAt[key]
+	return ok && !now.Before(expiresAt)
+}
+
+// deleteIfExpired removes the element if its TTL is over.
+// Returns true if the element was removed.
+func (c *Cache) deleteIfExpired(key interface{}) bool {
+	if !c.isExpired(key, c.clock.Now()) {
+		return false
+	}
+
+	c.delete(key, EvictedByTTL)
+	return true
+}
+
+// delete the element, count it in the stats and call the eviction function
+func (c *Cache) delete(key interface{}, reason EvictionReason) {
+	value, err := c.list.Get(key)
+	if err != nil {
+		return
+	}
+	c.list.remove(key)
+	delete(c.frequency, key)
+	delete(c.expiresAt, key)
+
+	if reason == EvictedByTTL {
+		c.stats.Expirations++
+	} else {
+		c.stats.Evictions++
+	}
+	if c.onEvict != nil {
+		c.onEvict(key, value, reason)
+	}
+}
+
+// evict removes the element chosen by the policy.
+// The expired elements are removed first.
+func (c *Cache) evict() error {
+	if c.DeleteExpired() > 0 {
+		return nil
+	}
+
+	key, _, err := c.list.GetFirst()
+	if err != nil {
+		return fmt.Errorf("list.GetFirst: %w", err)
+	}
+
+	if c.policy == LFU {
+		// the list goes from the least recently used,
+		// so the first element with the minimal frequency is chosen
+		minFrequency := c.frequency[key]
+		c.list.Range(func(rangeKey interface{}, _ interface{}) bool {
+			if c.frequency[rangeKey] < minFrequency {
+				key = rangeKey
+				minFrequency = c.frequency[rangeKey]
+			}
+			return minFrequency > 0
+		})
+	}
+
+	c.delete(key, EvictedByCap)
+
+	return nil
+}
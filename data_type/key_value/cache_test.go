@@ -0,0 +1,163 @@
+package key_value
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// testClock is the Clock that moves only when the test wants.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestCacheSuite struct {
+	suite.Suite
+	clock   *testClock
+	cache   *Cache
+	evicted []interface{}
+}
+
+// SetupTest prepares the LRU cache for three elements
+func (suite *TestCacheSuite) SetupTest() {
+	suite.clock = &testClock{now: time.Unix(1_000_000, 0)}
+	suite.evicted = make([]interface{}, 0)
+
+	cache := NewCache(LRU, suite.clock)
+	suite.Require().NoError(cache.SetCap(3))
+	cache.OnEvict(func(key interface{}, _ interface{}, _ EvictionReason) {
+		suite.evicted = append(suite.evicted, key)
+	})
+	suite.cache = cache
+
+	suite.Require().True(cache.IsEmpty())
+	suite.Require().Equal(LRU, cache.Policy())
+}
+
+func (suite *TestCacheSuite) TestLRU() {
+	suite.Require().NoError(suite.cache.Set("a", 1))
+	suite.Require().NoError(suite.cache.Set("b", 2))
+	suite.Require().NoError(suite.cache.Set("c", 3))
+
+	// "a" becomes the most recently used
+	value, err := suite.cache.Get("a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, value)
+
+	// the full cache evicts "b" instead of failing
+	suite.Require().NoError(suite.cache.Set("d", 4))
+	suite.Require().EqualValues(3, suite.cache.Len())
+	suite.Require().Equal([]interface{}{"b"}, suite.evicted)
+	suite.Require().False(suite.cache.Exist("b"))
+
+	_, err = suite.cache.Get("b")
+	suite.Require().Error(err)
+
+	// replacing the value doesn't evict
+	suite.Require().NoError(suite.cache.Set("c", 30))
+	suite.Require().Len(suite.evicted, 1)
+	value, err = suite.cache.Get("c")
+	suite.Require().NoError(err)
+	suite.Require().Equal(30, value)
+
+	suite.Require().Equal(CacheStats{Hits: 2, Misses: 1, Evictions: 1}, suite.cache.Stats())
+}
+
+func (suite *TestCacheSuite) TestListRules() {
+	suite.Require().NoError(suite.cache.Set("a", 1))
+
+	// the types must be the same as the first element
+	suite.Require().Error(suite.cache.Set(1, 1))
+	suite.Require().Error(suite.cache.Set("b", "1"))
+	suite.Require().Error(suite.cache.Set("b", nil))
+
+	// invalid element doesn't evict anything
+	suite.Require().NoError(suite.cache.Set("b", 2))
+	suite.Require().NoError(suite.cache.Set("c", 3))
+	suite.Require().Error(suite.cache.Set("d", "4"))
+	suite.Require().Empty(suite.evicted)
+	suite.Require().Error(suite.cache.SetCap(2))
+}
+
+func (suite *TestCacheSuite) TestLFU() {
+	cache := NewCache(LFU, suite.clock)
+	suite.Require().NoError(cache.SetCap(3))
+	suite.Require().Equal(LFU, cache.Policy())
+
+	suite.Require().NoError(cache.Set("a", 1))
+	suite.Require().NoError(cache.Set("b", 2))
+	suite.Require().NoError(cache.Set("c", 3))
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.Get("a")
+		suite.Require().NoError(err)
+	}
+	_, err := cache.Get("b")
+	suite.Require().NoError(err)
+	_, err = cache.Get("c")
+	suite.Require().NoError(err)
+
+	// "b" and "c" have the same frequency, "b" is least recently used
+	suite.Require().NoError(cache.Set("d", 4))
+	suite.Require().False(cache.Exist("b"))
+	suite.Require().True(cache.Exist("a"))
+	suite.Require().True(cache.Exist("c"))
+
+	// "d" was never used
+	suite.Require().NoError(cache.Set("e", 5))
+	suite.Require().False(cache.Exist("d"))
+}
+
+func (suite *TestCacheSuite) TestTTL() {
+	reasons := make([]EvictionReason, 0)
+	suite.cache.OnEvict(func(_ interface{}, _ interface{}, reason EvictionReason) {
+		reasons = append(reasons, reason)
+	})
+
+	suite.Require().Error(suite.cache.SetWithTTL("a", 1, -time.Second))
+	suite.Require().NoError(suite.cache.SetWithTTL("a", 1, time.Second))
+	suite.cache.SetTTL(time.Minute)
+	suite.Require().NoError(suite.cache.Set("b", 2))
+	suite.Require().NoError(suite.cache.SetWithTTL("c", 3, 0))
+
+	suite.clock.now = suite.clock.now.Add(2 * time.Second)
+	suite.Require().False(suite.cache.Exist("a"))
+	_, err := suite.cache.Get("a")
+	suite.Require().Error(err)
+	suite.Require().True(suite.cache.Exist("b"))
+
+	suite.clock.now = suite.clock.now.Add(time.Hour)
+	suite.Require().EqualValues(1, suite.cache.DeleteExpired())
+	suite.Require().True(suite.cache.Exist("c"))
+	suite.Require().EqualValues(1, suite.cache.Len())
+
+	suite.Require().Equal([]EvictionReason{EvictedByTTL, EvictedByTTL}, reasons)
+	suite.Require().Equal(CacheStats{Misses: 1, Expirations: 2}, suite.cache.Stats())
+}
+
+func (suite *TestCacheSuite) TestRemove() {
+	suite.Require().NoError(suite.cache.Set("a", 1))
+
+	value, err := suite.cache.Remove("a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, value)
+	_, err = suite.cache.Remove("a")
+	suite.Require().Error(err)
+
+	// removed by the user, not evicted
+	suite.Require().Empty(suite.evicted)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestCache(t *testing.T) {
+	suite.Run(t, new(TestCacheSuite))
+}
@@ -0,0 +1,438 @@
+package key_value
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// The major types of CBOR (RFC 8949)
+const (
+	cborUnsigned byte = iota
+	cborNegative
+	cborBytes
+	cborString
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// The tags of the big numbers (RFC 8949 section 3.4.3)
+const (
+	cborPositiveBigNum = 2
+	cborNegativeBigNum = 3
+)
+
+// cborSignedTag marks the positive int64, so NewFromCBOR keeps it signed.
+// CBOR has no signed type for the positive integers, and the tag is not registered,
+// so the other decoders return the unsigned integer itself.
+const cborSignedTag = 0x53445349 // "SDSI"
+
+// cborIndefinite is the additional information of the indefinite length items
+const cborIndefinite = 31
+
+// cborBreak ends the indefinite length item
+const cborBreak = 0xff
+
+// CBOR serializes k into the CBOR (RFC 8949) format.
+//
+// The *big.Int and the numbers that don't fit into 64 bits are written as the big numbers (tags 2 and 3).
+// The []byte is written as the byte string, not as base64 string.
+// The floats are always written in 64 bits, so they don't lose the precision.
+//
+// CBOR has no signed type for the positive integers,
+// so the positive int64 is wrapped into cborSignedTag, and NewFromCBOR decodes it as int64.
+// The negative integers are decoded as int64.
+//
+// The map keys are sorted by the deterministic encoding rules (RFC 8949 section 4.2.1),
+// so the same KeyValue gives the same bytes.
+func (k KeyValue) CBOR() ([]byte, error) {
+	value, err := binaryMap(k)
+	if err != nil {
+		return nil, fmt.Errorf("binaryMap: %w", err)
+	}
+
+	return appendCBOR(make([]byte, 0, 64), value), nil
+}
+
+// NewFromCBOR decodes the CBOR map into KeyValue.
+// The indefinite length items are supported.
+// The tags other than the big numbers and cborSignedTag are skipped, and their content is returned as it is.
+// The null and undefined values are not allowed, same as nil in NewFromString.
+func NewFromCBOR(data []byte) (KeyValue, error) {
+	r := &binaryReader{data: data}
+	value, err := readCBOR(r)
+	if err != nil {
+		return nil, fmt.Errorf("readCBOR: %w", err)
+	}
+
+	kv, err := binaryToKeyValue(value, r)
+	if err != nil {
+		return nil, fmt.Errorf("binaryToKeyValue: %w", err)
+	}
+
+	return kv, nil
+}
+
+// appendCBORHeader appends the major type with the argument in the shortest form
+func appendCBORHeader(buffer []byte, major byte, argument uint64) []byte {
+	major <<= 5
+	switch {
+	case argument < 24:
+		return append(buffer, major|byte(argument))
+	case argument <= math.MaxUint8:
+		return putUint(append(buffer, major|24), argument, 1)
+	case argument <= math.MaxUint16:
+		return putUint(append(buffer, major|25), argument, 2)
+	case argument <= math.MaxUint32:
+		return putUint(append(buffer, major|26), argument, 4)
+	}
+	return putUint(append(buffer, major|27), argument, 8)
+}
+
+// appendCBOR appends the value converted by binaryValue
+func appendCBOR(buffer []byte, value interface{}) []byte {
+	switch typed := value.(type) {
+	case bool:
+		if typed {
+			return append(buffer, 0xf5)
+		}
+		return append(buffer, 0xf4)
+	case uint64:
+		return appendCBORHeader(buffer, cborUnsigned, typed)
+	case int64:
+		if typed >= 0 {
+			buffer = appendCBORHeader(buffer, cborTag, cborSignedTag)
+			return appendCBORHeader(buffer, cborUnsigned, uint64(typed))
+		}
+		// the negative integer n is written as -1 - n
+		return appendCBORHeader(buffer, cborNegative, uint64(-(typed + 1)))
+	case float64:
+		return putUint(append(buffer, cborSimple<<5|27), math.Float64bits(typed), 8)
+	case string:
+		buffer = appendCBORHeader(buffer, cborString, uint64(len(typed)))
+		return append(buffer, typed...)
+	case []byte:
+		buffer = appendCBORHeader(buffer, cborBytes, uint64(len(typed)))
+		return append(buffer, typed...)
+	case *big.Int:
+		magnitude := typed
+		tag := uint64(cborPositiveBigNum)
+		if typed.Sign() < 0 {
+			// the negative big number n is written as -1 - n
+			magnitude = new(big.Int).Neg(typed)
+			magnitude.Sub(magnitude, big.NewInt(1))
+			tag = cborNegativeBigNum
+		}
+		buffer = appendCBORHeader(buffer, cborTag, tag)
+		return appendCBOR(buffer, magnitude.Bytes())
+	case []interface{}:
+		buffer = appendCBORHeader(buffer, cborArray, uint64(len(typed)))
+		for _, element := range typed {
+			buffer = appendCBOR(buffer, element)
+		}
+		return buffer
+	case map[string]interface{}:
+		buffer = appendCBORHeader(buffer, cborMap, uint64(len(typed)))
+
+		// the keys are sorted by their encoded bytes
+		type encodedKey struct {
+			key     string
+			encoded []byte
+		}
+		keys := make([]encodedKey, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, encodedKey{key: key, encoded: appendCBOR(nil, key)})
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return bytes.Compare(keys[i].encoded, keys[j].encoded) < 0
+		})
+		for _, key := range keys {
+			buffer = append(buffer, key.encoded...)
+			buffer = appendCBOR(buffer, typed[key.key])
+		}
+		return buffer
+	}
+
+	// binaryValue returns only the types above
+	panic(fmt.Sprintf("unsupported type %T", value))
+}
+
+// readCBORArgument reads the argument of the header.
+// Returns true if the item has the indefinite length.
+func readCBORArgument(r *binaryReader, info byte) (uint64, bool, error) {
+	switch {
+	case info < 24:
+		return uint64(info), false, nil
+	case info <= 27:
+		argument, err := r.readUint(1 << (info - 24))
+		return argument, false, err
+	case info == cborIndefinite:
+		return 0, true, nil
+	}
+
+	return 0, false, fmt.Errorf("reserved additional information %d at %d", info, r.offset-1)
+}
+
+// readCBOR reads one value
+func readCBOR(r *binaryReader) (interface{}, error) {
+	initial, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+	major := initial >> 5
+	info := initial & 0x1f
+
+	if major == cborSimple {
+		return readCBORSimple(r, info)
+	}
+
+	argument, indefinite, err := readCBORArgument(r, info)
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == cborUnsigned || major == cborNegative || major == cborTag) {
+		return nil, fmt.Errorf("major type %d can not have the indefinite length", major)
+	}
+
+	switch major {
+	case cborUnsigned:
+		return argument, nil
+	case cborNegative:
+		if argument <= math.MaxInt64 {
+			return -int64(argument) - 1, nil
+		}
+		number := new(big.Int).SetUint64(argument)
+		return number.Neg(number).Sub(number, big.NewInt(1)), nil
+	case cborBytes, cborString:
+		var b []byte
+		if indefinite {
+			b, err = readCBORChunks(r, major)
+		} else {
+			b, err = r.readBytes(argument)
+			b = append([]byte{}, b...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if major == cborString {
+			return string(b), nil
+		}
+		return b, nil
+	case cborArray:
+		return readCBORArray(r, argument, indefinite)
+	case cborMap:
+		return readCBORMap(r, argument, indefinite)
+	}
+
+	// the tag
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+	content, err := readCBOR(r)
+	if err != nil {
+		return nil, fmt.Errorf("tag %d: %w", argument, err)
+	}
+	if argument == cborSignedTag {
+		unsigned, ok := content.(uint64)
+		if !ok || unsigned > math.MaxInt64 {
+			return nil, fmt.Errorf("signed tag has %v, not a positive int64", content)
+		}
+		return int64(unsigned), nil
+	}
+	if argument != cborPositiveBigNum && argument != cborNegativeBigNum {
+		return content, nil
+	}
+
+	magnitude, ok := content.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("big number tag %d has %T, not bytes", argument, content)
+	}
+	number := new(big.Int).SetBytes(magnitude)
+	if argument == cborNegativeBigNum {
+		number.Neg(number).Sub(number, big.NewInt(1))
+	}
+
+	return number, nil
+}
+
+// readCBORSimple reads the simple values and the floats
+func readCBORSimple(r *binaryReader, info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, fmt.Errorf("value at %d is %w", r.offset-1, ErrNilValue)
+	case 25:
+		bits, err := r.readUint(2)
+		if err != nil {
+			return nil, err
+		}
+		return halfFloat(uint16(bits)), nil
+	case 26:
+		bits, err := r.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(bits))), nil
+	case 27:
+		bits, err := r.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits), nil
+	case cborIndefinite:
+		return nil, fmt.Errorf("unexpected break at %d", r.offset-1)
+	}
+
+	return nil, fmt.Errorf("unsupported simple value %d at %d", info, r.offset-1)
+}
+
+// halfFloat converts the IEEE 754 half precision float
+func halfFloat(bits uint16) float64 {
+	exponent := int(bits>>10) & 0x1f
+	mantissa := float64(bits & 0x3ff)
+
+	var value float64
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 0x1f:
+		if mantissa == 0 {
+			value = math.Inf(1)
+		} else {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+
+	if bits&0x8000 != 0 {
+		return -value
+	}
+	return value
+}
+
+// isCBORBreak returns true and skips the break byte, if it's the next
+func isCBORBreak(r *binaryReader) (bool, error) {
+	next, err := r.peekByte()
+	if err != nil {
+		return false, err
+	}
+	if next != cborBreak {
+		return false, nil
+	}
+	r.offset++
+
+	return true, nil
+}
+
+// readCBORChunks reads the indefinite length string as the definite length chunks
+func readCBORChunks(r *binaryReader, major byte) ([]byte, error) {
+	joined := make([]byte, 0)
+	for {
+		isBreak, err := isCBORBreak(r)
+		if err != nil {
+			return nil, err
+		}
+		if isBreak {
+			return joined, nil
+		}
+
+		initial, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if initial>>5 != major {
+			return nil, fmt.Errorf("chunk of major type %d in the string of major type %d", initial>>5, major)
+		}
+		length, indefinite, err := readCBORArgument(r, initial&0x1f)
+		if err != nil {
+			return nil, err
+		}
+		if indefinite {
+			return nil, fmt.Errorf("nested indefinite length chunk at %d", r.offset-1)
+		}
+		chunk, err := r.readBytes(length)
+		if err != nil {
+			return nil, err
+		}
+		joined = append(joined, chunk...)
+	}
+}
+
+func readCBORArray(r *binaryReader, length uint64, indefinite bool) ([]interface{}, error) {
+	if err := r.checkLength(length); err != nil {
+		return nil, err
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+
+	list := make([]interface{}, 0, length)
+	for i := 0; indefinite || uint64(i) < length; i++ {
+		if indefinite {
+			isBreak, err := isCBORBreak(r)
+			if err != nil {
+				return nil, err
+			}
+			if isBreak {
+				break
+			}
+		}
+
+		element, err := readCBOR(r)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		list = append(list, element)
+	}
+
+	return list, nil
+}
+
+func readCBORMap(r *binaryReader, length uint64, indefinite bool) (map[string]interface{}, error) {
+	if err := r.checkLength(length); err != nil {
+		return nil, err
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+
+	decoded := make(map[string]interface{}, length)
+	for i := 0; indefinite || uint64(i) < length; i++ {
+		if indefinite {
+			isBreak, err := isCBORBreak(r)
+			if err != nil {
+				return nil, err
+			}
+			if isBreak {
+				break
+			}
+		}
+
+		rawKey, err := readCBOR(r)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		key, ok := rawKey.(string)
+		if !ok {
+			return nil, fmt.Errorf("key %d is %T, not a string", i, rawKey)
+		}
+		value, err := readCBOR(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		decoded[key] = value
+	}
+
+	return decoded, nil
+}
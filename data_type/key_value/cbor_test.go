@@ -0,0 +1,152 @@
+package key_value
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestCBORSuite struct {
+	suite.Suite
+}
+
+// decode is the helper that decodes the hex of the CBOR data
+func (suite *TestCBORSuite) decode(data string) (KeyValue, error) {
+	raw, err := hex.DecodeString(data)
+	suite.Require().NoError(err)
+
+	return NewFromCBOR(raw)
+}
+
+func (suite *TestCBORSuite) TestJsonRoundTrip() {
+	kv, err := NewFromString(`{"big":123456789012345678901234567890,"enabled":true,"fraction":-1.5,` +
+		`"items":[{"qty":1},{"qty":70000}],"name":"sds","negative":-5,` +
+		`"neg_big":-123456789012345678901234567890,"tags":["a","b"],"uint":18446744073709551615}`)
+	suite.Require().NoError(err)
+
+	encoded, err := kv.CBOR()
+	suite.Require().NoError(err)
+	decoded, err := NewFromCBOR(encoded)
+	suite.Require().NoError(err)
+
+	suite.Require().True(kv.Equal(decoded))
+	suite.Require().Equal(kv.String(), decoded.String())
+
+	negative, err := decoded.Int64Value("negative")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-5), negative)
+	negBig, err := decoded.BigIntValue("neg_big")
+	suite.Require().NoError(err)
+	suite.Require().Equal("-123456789012345678901234567890", negBig.String())
+
+	// the same kv gives the same bytes
+	again, err := kv.CBOR()
+	suite.Require().NoError(err)
+	suite.Require().Equal(encoded, again)
+}
+
+func (suite *TestCBORSuite) TestTypes() {
+	kv := KeyValue{
+		"uint":     uint64(math.MaxUint64),
+		"negative": int64(math.MinInt64),
+		"float":    float64(5),
+		"big":      big.NewInt(1),
+		"bytes":    []byte{0, 1, 2},
+		"bool":     false,
+		"floats":   []float64{0.1},
+	}
+
+	encoded, err := kv.CBOR()
+	suite.Require().NoError(err)
+	decoded, err := NewFromCBOR(encoded)
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(uint64(math.MaxUint64), decoded["uint"])
+	suite.Require().Equal(int64(math.MinInt64), decoded["negative"])
+	suite.Require().Equal(float64(5), decoded["float"])
+	suite.Require().Equal(big.NewInt(1), decoded["big"])
+	suite.Require().Equal([]byte{0, 1, 2}, decoded["bytes"])
+	suite.Require().Equal(false, decoded["bool"])
+	suite.Require().Equal([]interface{}{0.1}, decoded["floats"])
+
+	// the positive signed integer stays signed
+	kv = KeyValue{"int": int64(5), "uint": uint64(5), "negative": int64(-5), "max": int64(math.MaxInt64)}
+	encoded, err = kv.CBOR()
+	suite.Require().NoError(err)
+	decoded, err = NewFromCBOR(encoded)
+	suite.Require().NoError(err)
+	suite.Require().Equal(kv, decoded)
+	suite.Require().Equal(kv.String(), decoded.String())
+
+	// the tag content must be the positive int64
+	_, err = suite.decode("a1616ada534453491b8000000000000000")
+	suite.Require().Error(err)
+	_, err = suite.decode("a1616ada534453496161")
+	suite.Require().Error(err)
+}
+
+func (suite *TestCBORSuite) TestFormat() {
+	// the keys are sorted by the encoded bytes, so the shorter key is first
+	encoded, err := KeyValue{"bb": -1, "a": []interface{}{uint64(24)}}.CBOR()
+	suite.Require().NoError(err)
+	suite.Require().Equal("a2616181181862626220", hex.EncodeToString(encoded))
+
+	// the examples of RFC 8949 appendix A
+	decoded, err := suite.decode("a3616dc249010000000000000000616e3bffffffffffffffff6168f93c00")
+	suite.Require().NoError(err)
+	twoPow64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	suite.Require().Equal(twoPow64, decoded["m"])
+	suite.Require().Equal(new(big.Int).Neg(twoPow64), decoded["n"])
+	suite.Require().Equal(1.0, decoded["h"])
+
+	// the indefinite length items
+	decoded, err = suite.decode("bf6346756ef563416d7421ff")
+	suite.Require().NoError(err)
+	suite.Require().Equal(KeyValue{"Fun": true, "Amt": int64(-2)}, decoded)
+	decoded, err = suite.decode("a1617a9f7f6261626163ff5f4101ffff")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]interface{}{"abc", []byte{1}}, decoded["z"])
+
+	// the other tags are skipped, float16 and float32 are converted
+	decoded, err = suite.decode("a36174c074323031332d30332d32315432303a30343a30305a6166f97bff6167fa47c35000")
+	suite.Require().NoError(err)
+	suite.Require().Equal("2013-03-21T20:04:00Z", decoded["t"])
+	suite.Require().Equal(65504.0, decoded["f"])
+	suite.Require().Equal(100000.0, decoded["g"])
+}
+
+func (suite *TestCBORSuite) TestInvalid() {
+	_, err := KeyValue{"nil": []interface{}{nil}}.CBOR()
+	suite.Require().ErrorIs(err, ErrNilValue)
+
+	for _, data := range []string{
+		"",                   // empty
+		"a16161f6",           // null
+		"a16161f7",           // undefined
+		"a16161",             // no value
+		"8161",               // not a map
+		"a10101",             // not a string key
+		"a1616101ff",         // bytes after the value
+		"a161617bffffffff",   // too long string
+		"a161619bffffffffff", // too long array
+		"a161611c",           // reserved additional information
+		"a16161ff",           // unexpected break
+		"a161611f",           // indefinite integer
+		"a16161c201",         // big number is not bytes
+	} {
+		_, err := suite.decode(data)
+		suite.Require().Error(err, data)
+	}
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestCBOR(t *testing.T) {
+	suite.Run(t, new(TestCBORSuite))
+}
@@ -0,0 +1,272 @@
+package key_value
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// canonicalFloat returns the shortest decimal format of the float.
+// The integral floats are written without the exponent and the fraction,
+// so float64(5) gives the same string as uint64(5).
+func canonicalFloat(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return new(big.Float).SetFloat64(f).Text('f', 0)
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// maxCanonicalDigits limits the zeros added by the exponent of the decimal,
+// so "1e1000000000" is not expanded into the billion of digits.
+// It's the same as MaxBigIntDigits of DefaultDecodeOptions.
+const maxCanonicalDigits = 1000
+
+// isDigits returns true if s has the decimal digits only
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// canonicalDecimal returns the decimal number in the canonical format.
+// Unlike float64, all the digits are kept, so "0.1" and "0.1000000000000000001" are different.
+// The integers are written in full, up to maxCanonicalDigits added by the exponent,
+// and the fractions are written in the same format as canonicalFloat does.
+// Returns false if s is not a decimal number.
+func canonicalDecimal(s string) (string, bool) {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign = "-"
+		s = s[1:]
+	}
+
+	mantissa, exponent := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exponent, err = strconv.Atoi(s[i+1:])
+		if err != nil || exponent > math.MaxInt32 || exponent < math.MinInt32 {
+			return "", false
+		}
+	}
+	integer, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		integer, fraction = mantissa[:i], mantissa[i+1:]
+		if len(fraction) == 0 {
+			return "", false
+		}
+	}
+	if len(integer) == 0 || !isDigits(integer) || !isDigits(fraction) {
+		return "", false
+	}
+
+	literal := len(integer) + len(fraction)
+	digits := strings.TrimLeft(integer+fraction, "0")
+	if len(digits) == 0 {
+		return "0", true
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(trimmed) - len(fraction)
+	digits = trimmed
+
+	// the number is 0.digits * 10^point
+	point := len(digits) + exponent
+	if point >= len(digits) {
+		if point <= maxCanonicalDigits || point <= literal {
+			return sign + digits + strings.Repeat("0", point-len(digits)), true
+		}
+	} else if point > -4 && point <= 6 {
+		if point <= 0 {
+			return sign + "0." + strings.Repeat("0", -point) + digits, true
+		}
+		return sign + digits[:point] + "." + digits[point:], true
+	}
+
+	// the exponent format, same as strconv.FormatFloat(f, 'g', -1, 64) gives
+	mantissa = digits[:1]
+	if len(digits) > 1 {
+		mantissa += "." + digits[1:]
+	}
+	exponentSign := "+"
+	exponent = point - 1
+	if exponent < 0 {
+		exponentSign = "-"
+		exponent = -exponent
+	}
+	return fmt.Sprintf("%s%se%s%02d", sign, mantissa, exponentSign, exponent), true
+}
+
+// canonicalNumber returns the number in the decimal format,
+// so the same number stored in different go types gives the same string.
+// The json.Number is converted by canonicalDecimal without rounding.
+// Returns false if the value is not a number.
+func canonicalNumber(value interface{}) (string, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		return canonicalDecimal(string(number))
+	case *big.Int:
+		if number == nil {
+			return "", false
+		}
+		return number.String(), true
+	case float64:
+		return canonicalFloat(number), true
+	case float32:
+		return canonicalFloat(float64(number)), true
+	case uint64:
+		return strconv.FormatUint(number, 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(number), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(number), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(number), 10), true
+	case uint:
+		return strconv.FormatUint(uint64(number), 10), true
+	case int64:
+		return strconv.FormatInt(number, 10), true
+	case int32:
+		return strconv.FormatInt(int64(number), 10), true
+	case int16:
+		return strconv.FormatInt(int64(number), 10), true
+	case int8:
+		return strconv.FormatInt(int64(number), 10), true
+	case int:
+		return strconv.FormatInt(int64(number), 10), true
+	}
+
+	return "", false
+}
+
+// maxPlainDigits is the amount of the digits in the integer written without the exponent.
+// strconv.FormatFloat writes the exponent from 1e21 too.
+const maxPlainDigits = 21
+
+// comparableNumber returns the number in the format used by Equal and Canonical.
+// It's canonicalNumber, where the integers from 1e21 are written in the exponent format,
+// so float64(1e21), json.Number("1e21") and big.Int of 10^21 give the same "1e+21".
+// Returns false if the value is not a number.
+func comparableNumber(value interface{}) (string, bool) {
+	number, ok := canonicalNumber(value)
+	if !ok {
+		return "", false
+	}
+
+	sign, digits := "", number
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= maxPlainDigits || !isDigits(digits) {
+		return number, true
+	}
+
+	trimmed := strings.TrimRight(digits, "0")
+	mantissa := trimmed[:1]
+	if len(trimmed) > 1 {
+		mantissa += "." + trimmed[1:]
+	}
+	return fmt.Sprintf("%s%se+%02d", sign, mantissa, len(digits)-1), true
+}
+
+// toList returns the elements of any slice except the bytes
+func toList(value interface{}) ([]interface{}, bool) {
+	switch list := value.(type) {
+	case []interface{}:
+		return list, true
+	case []byte:
+		return nil, false
+	}
+
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	list := make([]interface{}, reflected.Len())
+	for i := range list {
+		list[i] = reflected.Index(i).Interface()
+	}
+
+	return list, true
+}
+
+// valuesEqual compares two values of KeyValue.
+// The numbers are equal if they have the same value, regardless of the type.
+// The nested maps and lists are compared by their elements.
+func valuesEqual(a interface{}, b interface{}) bool {
+	aNumber, aOk := comparableNumber(a)
+	bNumber, bOk := comparableNumber(b)
+	if aOk || bOk {
+		return aOk && bOk && aNumber == bNumber
+	}
+
+	aMap, aOk := toKeyValue(a)
+	bMap, bOk := toKeyValue(b)
+	if aOk || bOk {
+		if !aOk || !bOk || len(aMap) != len(bMap) {
+			return false
+		}
+		for key, aValue := range aMap {
+			bValue, ok := bMap[key]
+			if !ok || !valuesEqual(aValue, bValue) {
+				return false
+			}
+		}
+		return true
+	}
+
+	aList, aOk := toList(a)
+	bList, bOk := toList(b)
+	if aOk || bOk {
+		if !aOk || !bOk || len(aList) != len(bList) {
+			return false
+		}
+		for i := range aList {
+			if !valuesEqual(aList[i], bList[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// Clone returns the deep copy of k.
+// The nested KeyValues, maps and lists are copied too,
+// so changing the clone doesn't change k.
+func (k KeyValue) Clone() KeyValue {
+	return k.deepCopy()
+}
+
+// Equal returns true if k and other have the same data.
+// The numbers are compared by the value, so json.Number("5"), uint64(5) and float64(5) are equal.
+// The decimals are compared as they are written, so float64(1.5e300) equals json.Number("1.5e300").
+// The nested maps and lists are compared by their elements.
+func (k KeyValue) Equal(other KeyValue) bool {
+	return valuesEqual(k, other)
+}
+
+// Hash returns the SHA-256 of the Canonical form in hex.
+// The equal KeyValues have the same hash, so it could be used as a key of the deduplication cache.
+// The decimals are compared exactly, so 0.1 and 0.1000000000000000001 have different hashes.
+func (k KeyValue) Hash() (string, error) {
+	canonical, err := k.Canonical()
+	if err != nil {
+		return "", fmt.Errorf("k.Canonical: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,154 @@
+package key_value
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestCompareSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+// SetupTest creates the kv-value with all kinds of nested values
+func (suite *TestCompareSuite) SetupTest() {
+	suite.kv = KeyValue{
+		"number":  json.Number("5"),
+		"supply":  big.NewInt(100),
+		"tags":    []string{"a", "b"},
+		"ids":     []uint64{1, 2},
+		"raw":     []byte{1, 2},
+		"nested":  KeyValue{"list": []interface{}{map[string]interface{}{"qty": uint64(1)}}},
+		"items":   []KeyValue{{"qty": float64(2)}},
+		"enabled": true,
+	}
+}
+
+func (suite *TestCompareSuite) TestClone() {
+	clone := suite.kv.Clone()
+	suite.Require().True(clone.Equal(suite.kv))
+
+	// changing the clone doesn't change the original
+	clone["tags"].([]string)[0] = "changed"
+	clone["ids"].([]uint64)[0] = 100
+	clone["raw"].([]byte)[0] = 100
+	clone["supply"].(*big.Int).SetInt64(1)
+	clone["items"].([]KeyValue)[0]["qty"] = float64(3)
+	suite.Require().NoError(clone.SetAt("nested.list.0.qty", uint64(2)))
+
+	suite.Require().Equal([]string{"a", "b"}, suite.kv["tags"])
+	suite.Require().Equal([]uint64{1, 2}, suite.kv["ids"])
+	suite.Require().Equal([]byte{1, 2}, suite.kv["raw"])
+	suite.Require().Equal(big.NewInt(100), suite.kv["supply"])
+	suite.Require().Equal(float64(2), suite.kv["items"].([]KeyValue)[0]["qty"])
+	qty, err := suite.kv.Uint64At("nested.list.0.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(1), qty)
+	suite.Require().False(clone.Equal(suite.kv))
+
+	var empty KeyValue
+	suite.Require().Nil(empty.Clone())
+}
+
+func (suite *TestCompareSuite) TestEqual() {
+	a := KeyValue{"a": json.Number("5"), "b": []interface{}{"x"}, "c": map[string]interface{}{"d": 1.5}}
+	b := KeyValue{"a": uint64(5), "b": []string{"x"}, "c": KeyValue{"d": json.Number("1.50")}}
+	c := KeyValue{"a": float64(5), "b": []string{"x"}, "c": KeyValue{"d": json.Number("1.5")}}
+
+	suite.Require().True(a.Equal(b))
+	suite.Require().True(b.Equal(c))
+
+	suite.Require().False(a.Equal(KeyValue{"a": "5", "b": []string{"x"}, "c": KeyValue{"d": 1.5}}))
+	suite.Require().False(a.Equal(KeyValue{"a": 5}))
+	suite.Require().False(a.Equal(KeyValue{"a": 5, "b": []string{"x", "y"}, "c": KeyValue{"d": 1.5}}))
+}
+
+func (suite *TestCompareSuite) TestHash() {
+	a := KeyValue{"a": json.Number("5"), "b": []interface{}{"x"}, "c": map[string]interface{}{"d": 1.5}}
+	b := KeyValue{"c": KeyValue{"d": json.Number("1.50")}, "b": []string{"x"}, "a": uint64(5)}
+
+	hashA, err := a.Hash()
+	suite.Require().NoError(err)
+	hashB, err := b.Hash()
+	suite.Require().NoError(err)
+	suite.Require().Equal(hashA, hashB)
+	suite.Require().Len(hashA, 64)
+
+	// the hash is stable
+	again, err := a.Hash()
+	suite.Require().NoError(err)
+	suite.Require().Equal(hashA, again)
+
+	b["a"] = uint64(6)
+	hashB, err = b.Hash()
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(hashA, hashB)
+
+	// the different decimals are not rounded to the same float
+	decimal, err := NewFromString(`{"p":0.1}`)
+	suite.Require().NoError(err)
+	longDecimal, err := NewFromString(`{"p":0.1000000000000000001}`)
+	suite.Require().NoError(err)
+	hashA, err = decimal.Hash()
+	suite.Require().NoError(err)
+	hashB, err = longDecimal.Hash()
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(hashA, hashB)
+	suite.Require().False(decimal.Equal(longDecimal))
+
+	// the same decimal in the different formats
+	hashB, err = KeyValue{"p": json.Number("1.0e-1")}.Hash()
+	suite.Require().NoError(err)
+	suite.Require().Equal(hashA, hashB)
+	hashB, err = KeyValue{"p": 0.1}.Hash()
+	suite.Require().NoError(err)
+	suite.Require().Equal(hashA, hashB)
+
+	// the large numbers in the different go types
+	tenPow21, ok := new(big.Int).SetString("1000000000000000000000", 10)
+	suite.Require().True(ok)
+	large := []KeyValue{
+		{"x": float64(1e21)},
+		{"x": json.Number("1e21")},
+		{"x": json.Number("1000000000000000000000")},
+		{"x": json.Number("10.0e20")},
+		{"x": tenPow21},
+	}
+	fromString, err := NewFromString(`{"x":1e21}`)
+	suite.Require().NoError(err)
+	large = append(large, fromString)
+	hashA, err = large[0].Hash()
+	suite.Require().NoError(err)
+	canonical, err := large[0].Canonical()
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{"x":1e+21}`, string(canonical))
+	for i, kv := range large {
+		suite.Require().True(large[0].Equal(kv), i)
+		hashB, err = kv.Hash()
+		suite.Require().NoError(err)
+		suite.Require().Equal(hashA, hashB, i)
+	}
+
+	hashA, err = KeyValue{"x": float64(1.5e300)}.Hash()
+	suite.Require().NoError(err)
+	hashB, err = KeyValue{"x": json.Number("1.5e300")}.Hash()
+	suite.Require().NoError(err)
+	suite.Require().Equal(hashA, hashB)
+	suite.Require().True(KeyValue{"x": float64(1.5e300)}.Equal(KeyValue{"x": json.Number("1.5e300")}))
+	suite.Require().False(KeyValue{"x": tenPow21}.Equal(KeyValue{"x": json.Number("1000000000000000000001")}))
+
+	_, err = KeyValue{"nil": nil}.Hash()
+	suite.Require().Error(err)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestCompare(t *testing.T) {
+	suite.Run(t, new(TestCompareSuite))
+}
@@ -0,0 +1,182 @@
+package key_value
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The errors returned when the decoded data exceeds the DecodeOptions.
+// ErrTooLarge and ErrTooDeep are defined with the Decoder.
+var (
+	ErrTooManyKeys   = errors.New("too many keys")
+	ErrStringTooLong = errors.New("string too long")
+	ErrTooManyDigits = errors.New("too many digits")
+	ErrDuplicateKey  = errors.New("duplicate key")
+)
+
+// DecodeOptions limits the json accepted from the untrusted sources, like the network.
+// The zero value of the field means no limit.
+//
+// The data is checked before it's decoded,
+// so the data exceeding the limits doesn't allocate the KeyValue.
+type DecodeOptions struct {
+	MaxDepth              int   // The nesting of the objects and arrays, where the top level object is 1
+	MaxKeys               int   // The amount of the keys in one object
+	MaxBytes              int64 // The size of the json
+	MaxStringLen          int   // The length of the strings and the keys in bytes
+	MaxBigIntDigits       int   // The amount of the digits in the number, the exponent adds its absolute value
+	DisallowDuplicateKeys bool  // Return ErrDuplicateKey instead of keeping the last value
+}
+
+// maxCountedExponent caps the exponent counted by numberDigits, so the huge exponents don't overflow int
+const maxCountedExponent = 1 << 24
+
+// DefaultDecodeOptions returns the limits used by the message package.
+// They are large enough for any valid message, but stop the abusive ones.
+func DefaultDecodeOptions() DecodeOptions {
+	return DecodeOptions{
+		MaxDepth:        DefaultMaxDepth,
+		MaxKeys:         10_000,
+		MaxBytes:        DefaultMaxBytes,
+		MaxStringLen:    DefaultMaxBytes,
+		MaxBigIntDigits: 1000,
+	}
+}
+
+// NewFromStringWithOptions is NewFromString that returns an error if s exceeds the options
+func NewFromStringWithOptions(s string, options DecodeOptions) (KeyValue, error) {
+	if err := options.check([]byte(s)); err != nil {
+		return nil, fmt.Errorf("options: %w", err)
+	}
+
+	return NewFromString(s)
+}
+
+// limited returns true if any limit besides MaxBytes is set
+func (options DecodeOptions) limited() bool {
+	return options.MaxDepth > 0 || options.MaxKeys > 0 || options.MaxStringLen > 0 ||
+		options.MaxBigIntDigits > 0 || options.DisallowDuplicateKeys
+}
+
+// decodeLevel is the object or array opened in the json
+type decodeLevel struct {
+	object    bool
+	expectKey bool // The next string is the key of the object
+	keys      int
+	seen      map[string]struct{}
+}
+
+// check walks through the tokens of the json, and returns an error if any limit is exceeded.
+// The invalid json is not reported, it's left for the decoder.
+func (options DecodeOptions) check(data []byte) error {
+	if options.MaxBytes > 0 && int64(len(data)) > options.MaxBytes {
+		return fmt.Errorf("%d bytes exceed %d: %w", len(data), options.MaxBytes, ErrTooLarge)
+	}
+	if !options.limited() {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	stack := make([]*decodeLevel, 0)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		var parent *decodeLevel
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			if delim == '}' || delim == ']' {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			if parent != nil && parent.object {
+				parent.expectKey = true
+			}
+			level := &decodeLevel{object: delim == '{', expectKey: delim == '{'}
+			if options.DisallowDuplicateKeys && level.object {
+				level.seen = make(map[string]struct{})
+			}
+			stack = append(stack, level)
+			if options.MaxDepth > 0 && len(stack) > options.MaxDepth {
+				return fmt.Errorf("nesting at %d exceeds %d levels: %w", decoder.InputOffset(), options.MaxDepth, ErrTooDeep)
+			}
+			continue
+		}
+
+		if parent != nil && parent.object && parent.expectKey {
+			if err := options.checkKey(parent, token.(string), decoder.InputOffset()); err != nil {
+				return err
+			}
+			parent.expectKey = false
+			continue
+		}
+		if parent != nil && parent.object {
+			parent.expectKey = true
+		}
+
+		switch typed := token.(type) {
+		case string:
+			if options.MaxStringLen > 0 && len(typed) > options.MaxStringLen {
+				return fmt.Errorf("string of %d bytes at %d exceeds %d: %w",
+					len(typed), decoder.InputOffset(), options.MaxStringLen, ErrStringTooLong)
+			}
+		case json.Number:
+			if digits := numberDigits(typed); options.MaxBigIntDigits > 0 && digits > options.MaxBigIntDigits {
+				return fmt.Errorf("number of %d digits at %d exceeds %d: %w",
+					digits, decoder.InputOffset(), options.MaxBigIntDigits, ErrTooManyDigits)
+			}
+		}
+	}
+}
+
+// checkKey checks the key of the object
+func (options DecodeOptions) checkKey(object *decodeLevel, key string, offset int64) error {
+	if options.MaxStringLen > 0 && len(key) > options.MaxStringLen {
+		return fmt.Errorf("key of %d bytes at %d exceeds %d: %w", len(key), offset, options.MaxStringLen, ErrStringTooLong)
+	}
+
+	object.keys++
+	if options.MaxKeys > 0 && object.keys > options.MaxKeys {
+		return fmt.Errorf("object at %d has more than %d keys: %w", offset, options.MaxKeys, ErrTooManyKeys)
+	}
+
+	if object.seen != nil {
+		if _, ok := object.seen[key]; ok {
+			return fmt.Errorf("'%s' at %d: %w", key, offset, ErrDuplicateKey)
+		}
+		object.seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// numberDigits returns the amount of the digits that the number expands into:
+// the digits before the exponent, plus the absolute value of the exponent.
+// The exponent stops counting once it's more than maxCountedExponent.
+func numberDigits(number json.Number) int {
+	digits, exponent := 0, 0
+	inExponent := false
+	for _, c := range number {
+		switch {
+		case c == 'e' || c == 'E':
+			inExponent = true
+		case c < '0' || c > '9':
+		case !inExponent:
+			digits++
+		case exponent <= maxCountedExponent:
+			exponent = exponent*10 + int(c-'0')
+		}
+	}
+
+	return digits + exponent
+}
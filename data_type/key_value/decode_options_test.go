@@ -0,0 +1,122 @@
+package key_value
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestDecodeOptionsSuite struct {
+	suite.Suite
+}
+
+func (suite *TestDecodeOptionsSuite) TestNoLimits() {
+	kv, err := NewFromStringWithOptions(`{"a":{"b":[{"c":1}]},"a":2}`, DecodeOptions{})
+	suite.Require().NoError(err)
+	// the last duplicate key is kept, same as NewFromString does
+	suite.Require().Equal(uint64(2), kv.Uint64Or("a", 0))
+
+	_, err = NewFromStringWithOptions(`{"a":`, DecodeOptions{MaxDepth: 1})
+	suite.Require().Error(err)
+}
+
+func (suite *TestDecodeOptionsSuite) TestMaxDepth() {
+	options := DecodeOptions{MaxDepth: 3}
+
+	_, err := NewFromStringWithOptions(`{"a":{"b":[1]}}`, options)
+	suite.Require().NoError(err)
+	_, err = NewFromStringWithOptions(`{"a":{"b":[{"c":1}]}}`, options)
+	suite.Require().ErrorIs(err, ErrTooDeep)
+	// the brackets in the strings are not counted
+	_, err = NewFromStringWithOptions(`{"a":"[[[{{{"}`, DecodeOptions{MaxDepth: 1})
+	suite.Require().NoError(err)
+}
+
+func (suite *TestDecodeOptionsSuite) TestMaxKeys() {
+	options := DecodeOptions{MaxKeys: 2}
+
+	// the keys are counted in each object
+	_, err := NewFromStringWithOptions(`{"a":{"c":1,"d":2},"b":[{"e":1,"f":2}]}`, options)
+	suite.Require().NoError(err)
+	_, err = NewFromStringWithOptions(`{"a":1,"b":2,"c":3}`, options)
+	suite.Require().ErrorIs(err, ErrTooManyKeys)
+	_, err = NewFromStringWithOptions(`{"a":{"c":1,"d":2,"e":3}}`, options)
+	suite.Require().ErrorIs(err, ErrTooManyKeys)
+}
+
+func (suite *TestDecodeOptionsSuite) TestMaxBytes() {
+	_, err := NewFromStringWithOptions(`{"a":1}`, DecodeOptions{MaxBytes: 7})
+	suite.Require().NoError(err)
+	_, err = NewFromStringWithOptions(`{"a":12}`, DecodeOptions{MaxBytes: 7})
+	suite.Require().ErrorIs(err, ErrTooLarge)
+}
+
+func (suite *TestDecodeOptionsSuite) TestMaxStringLen() {
+	options := DecodeOptions{MaxStringLen: 3}
+
+	_, err := NewFromStringWithOptions(`{"abc":"abc","list":["abc"]}`, options)
+	suite.Require().Error(err)
+	_, err = NewFromStringWithOptions(`{"abc":"abc","lst":["abc"]}`, options)
+	suite.Require().NoError(err)
+	_, err = NewFromStringWithOptions(`{"abc":"abcd"}`, options)
+	suite.Require().ErrorIs(err, ErrStringTooLong)
+	_, err = NewFromStringWithOptions(`{"abcd":"abc"}`, options)
+	suite.Require().ErrorIs(err, ErrStringTooLong)
+	_, err = NewFromStringWithOptions(`{"a":["abc","abcd"]}`, options)
+	suite.Require().ErrorIs(err, ErrStringTooLong)
+}
+
+func (suite *TestDecodeOptionsSuite) TestMaxBigIntDigits() {
+	options := DecodeOptions{MaxBigIntDigits: 30}
+
+	kv, err := NewFromStringWithOptions(`{"supply":-123456789012345678901234567890,"price":1.5e28,"small":-2.5E-28}`, options)
+	suite.Require().NoError(err)
+	supply, err := kv.BigIntValue("supply")
+	suite.Require().NoError(err)
+	suite.Require().Equal("-123456789012345678901234567890", supply.String())
+
+	_, err = NewFromStringWithOptions(`{"supply":1`+strings.Repeat("0", 30)+`}`, options)
+	suite.Require().ErrorIs(err, ErrTooManyDigits)
+	_, err = NewFromStringWithOptions(`{"list":[0.`+strings.Repeat("1", 30)+`]}`, options)
+	suite.Require().ErrorIs(err, ErrTooManyDigits)
+
+	// the exponent counts toward the limit
+	for _, number := range []string{`1.5e300`, `1e30`, `12E+29`, `1e-30`, `1e999999999`, `1e99999999999999999999999`} {
+		_, err = NewFromStringWithOptions(`{"n":`+number+`}`, options)
+		suite.Require().ErrorIs(err, ErrTooManyDigits, number)
+	}
+	_, err = NewFromStringWithOptions(`{"n":1e999999999}`, DefaultDecodeOptions())
+	suite.Require().ErrorIs(err, ErrTooManyDigits)
+}
+
+func (suite *TestDecodeOptionsSuite) TestDuplicateKeys() {
+	options := DecodeOptions{DisallowDuplicateKeys: true}
+
+	// the same key in the different objects is not a duplicate
+	_, err := NewFromStringWithOptions(`{"a":{"a":1},"b":[{"a":1},{"a":2}]}`, options)
+	suite.Require().NoError(err)
+	_, err = NewFromStringWithOptions(`{"a":1,"a":2}`, options)
+	suite.Require().ErrorIs(err, ErrDuplicateKey)
+	_, err = NewFromStringWithOptions(`{"b":[{"a":1,"a":2}]}`, options)
+	suite.Require().ErrorIs(err, ErrDuplicateKey)
+}
+
+func (suite *TestDecodeOptionsSuite) TestDecoder() {
+	decoder := NewDecoder(strings.NewReader("{\"a\":1}\n{\"a\":1,\"a\":2}\n"))
+	decoder.SetOptions(DecodeOptions{DisallowDuplicateKeys: true})
+
+	_, err := decoder.Decode()
+	suite.Require().NoError(err)
+	_, err = decoder.Decode()
+	suite.Require().ErrorIs(err, ErrDuplicateKey)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestDecodeOptions(t *testing.T) {
+	suite.Run(t, new(TestDecodeOptionsSuite))
+}
@@ -0,0 +1,75 @@
+package key_value
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors returned by the getters.
+// Use errors.Is to check them:
+//
+//	if errors.Is(err, key_value.ErrKeyNotFound) {
+//		// use the default value
+//	}
+var (
+	// ErrKeyNotFound means the key or the path doesn't exist
+	ErrKeyNotFound = errors.New("not exist")
+	// ErrTypeMismatch means the value can not be converted into the requested type.
+	// The details are in TypeMismatchError.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrNilValue means the value is nil, which KeyValue doesn't allow
+	ErrNilValue = errors.New("nil")
+)
+
+// TypeMismatchError is returned when the value can not be converted into the requested type.
+// It matches ErrTypeMismatch in errors.Is.
+// Use errors.As to get the details:
+//
+//	var mismatch *key_value.TypeMismatchError
+//	if errors.As(err, &mismatch) {
+//		fmt.Println(mismatch.Key, mismatch.Actual)
+//	}
+type TypeMismatchError struct {
+	Key      string
+	Expected string // The requested type
+	Actual   string // The go type of the value
+	Err      error  // The conversion error, if the type is right, but the value can not be parsed
+}
+
+func (e *TypeMismatchError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("'%s' parameter type %s, can not convert to %s: '%v'", e.Key, e.Actual, e.Expected, e.Err)
+	}
+	return fmt.Sprintf("'%s' parameter type %s, can not convert to %s", e.Key, e.Actual, e.Expected)
+}
+
+// Is returns true for ErrTypeMismatch
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// Unwrap returns the conversion error
+func (e *TypeMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// notExist returns ErrKeyNotFound for the key
+func notExist(key string) error {
+	return fmt.Errorf("'%s' %w", key, ErrKeyNotFound)
+}
+
+// nilValue returns ErrNilValue for the key
+func nilValue(key string) error {
+	return fmt.Errorf("kv %s is %w", key, ErrNilValue)
+}
+
+// typeMismatch returns TypeMismatchError for the raw value.
+// The err is the conversion error, could be nil.
+func typeMismatch(key string, raw interface{}, expected string, err error) error {
+	return &TypeMismatchError{
+		Key:      key,
+		Expected: expected,
+		Actual:   fmt.Sprintf("%T", raw),
+		Err:      err,
+	}
+}
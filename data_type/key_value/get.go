@@ -0,0 +1,139 @@
+package key_value
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Get returns the value converted into T by the getter of the type:
+//
+//	amount, err := key_value.Get[uint64](kv, "amount") // same as kv.Uint64Value("amount")
+//
+// The int, uint and float32 are converted by Int64Value, Uint64Value and Float64Value,
+// and the value that doesn't fit into T is the type mismatch.
+// The types without the getter are returned if the value has exactly the type T.
+func Get[T any](kv KeyValue, key string) (T, error) {
+	var zero T
+	var value interface{}
+	var err error
+
+	switch any(zero).(type) {
+	case uint64:
+		value, err = kv.Uint64Value(key)
+	case uint32:
+		value, err = kv.Uint32Value(key)
+	case int64:
+		value, err = kv.Int64Value(key)
+	case int32:
+		value, err = kv.Int32Value(key)
+	case int:
+		value, err = kv.intValue(key)
+	case uint:
+		value, err = kv.uintValue(key)
+	case float64:
+		value, err = kv.Float64Value(key)
+	case float32:
+		value, err = kv.float32Value(key)
+	case bool:
+		value, err = kv.BoolValue(key)
+	case string:
+		value, err = kv.StringValue(key)
+	case []string:
+		value, err = kv.StringsValue(key)
+	case []uint64:
+		value, err = kv.Uint64sValue(key)
+	case []float64:
+		value, err = kv.Float64sValue(key)
+	case *big.Int:
+		value, err = kv.BigIntValue(key)
+	case *big.Float:
+		value, err = kv.BigFloatValue(key)
+	case time.Duration:
+		value, err = kv.DurationValue(key)
+	case time.Time:
+		value, err = kv.TimeValue(key)
+	case []byte:
+		value, err = kv.BytesValue(key)
+	case KeyValue:
+		value, err = kv.NestedValue(key)
+	case map[string]interface{}:
+		var nested KeyValue
+		nested, err = kv.NestedValue(key)
+		value = map[string]interface{}(nested)
+	case []KeyValue:
+		value, err = kv.NestedListValue(key)
+	default:
+		if !kv.Exist(key) {
+			return zero, notExist(key)
+		}
+		raw := kv[key]
+		if raw == nil {
+			return zero, nilValue(key)
+		}
+		typed, ok := raw.(T)
+		if !ok {
+			return zero, typeMismatch(key, raw, reflect.TypeOf(&zero).Elem().String(), nil)
+		}
+		return typed, nil
+	}
+
+	if err != nil {
+		return zero, err
+	}
+
+	return value.(T), nil
+}
+
+// GetAt returns the value by the dotted path converted into T, same as Get does
+func GetAt[T any](kv KeyValue, path string) (T, error) {
+	leaf, key, err := kv.leafAt(path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return Get[T](leaf, key)
+}
+
+// intValue returns the parameter as an int
+func (k KeyValue) intValue(key string) (int, error) {
+	value, err := k.Int64Value(key)
+	if err != nil {
+		return 0, err
+	}
+	if value < math.MinInt || value > math.MaxInt {
+		return 0, typeMismatch(key, k[key], "int", fmt.Errorf("%d: %w", value, strconv.ErrRange))
+	}
+
+	return int(value), nil
+}
+
+// uintValue returns the parameter as an uint
+func (k KeyValue) uintValue(key string) (uint, error) {
+	value, err := k.Uint64Value(key)
+	if err != nil {
+		return 0, err
+	}
+	if value > math.MaxUint {
+		return 0, typeMismatch(key, k[key], "uint", fmt.Errorf("%d: %w", value, strconv.ErrRange))
+	}
+
+	return uint(value), nil
+}
+
+// float32Value returns the parameter as a float32
+func (k KeyValue) float32Value(key string) (float32, error) {
+	value, err := k.Float64Value(key)
+	if err != nil {
+		return 0, err
+	}
+	if math.Abs(value) > math.MaxFloat32 {
+		return 0, typeMismatch(key, k[key], "float32", fmt.Errorf("%g: %w", value, strconv.ErrRange))
+	}
+
+	return float32(value), nil
+}
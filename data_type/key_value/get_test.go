@@ -0,0 +1,146 @@
+package key_value
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestGetSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+// SetupTest parses the parameters the same way as the messages are parsed
+func (suite *TestGetSuite) SetupTest() {
+	kv, err := NewFromString(`{"amount":5,"delta":-5,"price":1.5,"name":"sds","paid":true,
+		"tags":["a"],"supply":123456789012345678901234567890,"timeout":"1s",
+		"order":{"items":[{"qty":2}]}}`)
+	suite.Require().NoError(err)
+	suite.kv = kv
+}
+
+func (suite *TestGetSuite) TestGet() {
+	amount, err := Get[uint64](suite.kv, "amount")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(5), amount)
+
+	delta, err := Get[int64](suite.kv, "delta")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-5), delta)
+
+	price, err := Get[float64](suite.kv, "price")
+	suite.Require().NoError(err)
+	suite.Require().Equal(1.5, price)
+
+	// the go types without the getter are converted too
+	amountInt, err := Get[int](suite.kv, "amount")
+	suite.Require().NoError(err)
+	suite.Require().Equal(5, amountInt)
+	deltaInt, err := Get[int](suite.kv, "delta")
+	suite.Require().NoError(err)
+	suite.Require().Equal(-5, deltaInt)
+	amountUint, err := Get[uint](suite.kv, "amount")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint(5), amountUint)
+	price32, err := Get[float32](suite.kv, "price")
+	suite.Require().NoError(err)
+	suite.Require().Equal(float32(1.5), price32)
+
+	name, err := Get[string](suite.kv, "name")
+	suite.Require().NoError(err)
+	suite.Require().Equal("sds", name)
+
+	paid, err := Get[bool](suite.kv, "paid")
+	suite.Require().NoError(err)
+	suite.Require().True(paid)
+
+	tags, err := Get[[]string](suite.kv, "tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a"}, tags)
+
+	supply, err := Get[*big.Int](suite.kv, "supply")
+	suite.Require().NoError(err)
+	suite.Require().Equal("123456789012345678901234567890", supply.String())
+
+	timeout, err := Get[time.Duration](suite.kv, "timeout")
+	suite.Require().NoError(err)
+	suite.Require().Equal(time.Second, timeout)
+
+	order, err := Get[KeyValue](suite.kv, "order")
+	suite.Require().NoError(err)
+	suite.Require().True(order.Exist("items"))
+
+	orderMap, err := Get[map[string]interface{}](suite.kv, "order")
+	suite.Require().NoError(err)
+	suite.Require().Contains(orderMap, "items")
+
+	// the types without the getter are returned as they are
+	kv := KeyValue{"point": struct{ X int }{X: 1}}
+	point, err := Get[struct{ X int }](kv, "point")
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, point.X)
+
+	qty, err := GetAt[uint64](suite.kv, "order.items.0.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(2), qty)
+}
+
+func (suite *TestGetSuite) TestErrors() {
+	_, err := Get[uint64](suite.kv, "not_exist")
+	suite.Require().ErrorIs(err, ErrKeyNotFound)
+	suite.Require().NotErrorIs(err, ErrTypeMismatch)
+
+	_, err = GetAt[uint64](suite.kv, "order.items.1.qty")
+	suite.Require().ErrorIs(err, ErrKeyNotFound)
+	_, err = suite.kv.StringAt("order.not_exist")
+	suite.Require().ErrorIs(err, ErrKeyNotFound)
+
+	_, err = Get[uint64](suite.kv, "name")
+	suite.Require().ErrorIs(err, ErrTypeMismatch)
+	suite.Require().NotErrorIs(err, ErrKeyNotFound)
+
+	var mismatch *TypeMismatchError
+	suite.Require().True(errors.As(err, &mismatch))
+	suite.Require().Equal("name", mismatch.Key)
+	suite.Require().Equal("string", mismatch.Actual)
+	suite.Require().Error(mismatch.Unwrap())
+
+	_, err = Get[bool](suite.kv, "amount")
+	suite.Require().True(errors.As(err, &mismatch))
+	suite.Require().Equal("json.Number", mismatch.Actual)
+	suite.Require().Nil(mismatch.Unwrap())
+
+	_, err = Get[struct{}](suite.kv, "amount")
+	suite.Require().ErrorIs(err, ErrTypeMismatch)
+
+	// overflow is the type mismatch
+	_, err = Get[int32](KeyValue{"large": uint64(1) << 40}, "large")
+	suite.Require().ErrorIs(err, ErrTypeMismatch)
+	_, err = Get[int](suite.kv, "supply")
+	suite.Require().ErrorIs(err, ErrTypeMismatch)
+	_, err = Get[uint](suite.kv, "delta")
+	suite.Require().ErrorIs(err, ErrTypeMismatch)
+	_, err = Get[float32](KeyValue{"large": 1e300}, "large")
+	suite.Require().ErrorIs(err, ErrTypeMismatch)
+
+	kv := KeyValue{"nil": nil}
+	_, err = Get[string](kv, "nil")
+	suite.Require().ErrorIs(err, ErrNilValue)
+	_, err = Get[[]KeyValue](kv, "nil")
+	suite.Require().ErrorIs(err, ErrNilValue)
+	_, err = Get[struct{}](kv, "nil")
+	suite.Require().ErrorIs(err, ErrNilValue)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestGet(t *testing.T) {
+	suite.Run(t, new(TestGetSuite))
+}
@@ -0,0 +1,407 @@
+package key_value
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// Int64Value returns the parameter as an int64.
+// Unlike Uint64Value, the float is accepted only if it has no fraction.
+func (k KeyValue) Int64Value(key string) (int64, error) {
+	if !k.Exist(key) {
+		return 0, notExist(key)
+	}
+	raw := k[key]
+	if raw == nil {
+		return 0, nilValue(key)
+	}
+
+	number, ok := canonicalNumber(raw)
+	if !ok {
+		stringValue, ok := raw.(string)
+		if !ok {
+			return 0, typeMismatch(key, raw, "number", nil)
+		}
+		number = stringValue
+	}
+
+	value, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, typeMismatch(key, raw, "int64", err)
+	}
+
+	return value, nil
+}
+
+// Int32Value returns the parameter as an int32
+func (k KeyValue) Int32Value(key string) (int32, error) {
+	value, err := k.Int64Value(key)
+	if err != nil {
+		return 0, err
+	}
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return 0, typeMismatch(key, k[key], "int32", fmt.Errorf("%d: %w", value, strconv.ErrRange))
+	}
+
+	return int32(value), nil
+}
+
+// Uint32Value returns the parameter as an uint32
+func (k KeyValue) Uint32Value(key string) (uint32, error) {
+	value, err := k.Uint64Value(key)
+	if err != nil {
+		return 0, err
+	}
+	if value > math.MaxUint32 {
+		return 0, typeMismatch(key, k[key], "uint32", fmt.Errorf("%d: %w", value, strconv.ErrRange))
+	}
+
+	return uint32(value), nil
+}
+
+// DurationValue returns the parameter as a duration.
+// The string is parsed by time.ParseDuration ("1m30s"),
+// the number is treated as nanoseconds, same as json does.
+func (k KeyValue) DurationValue(key string) (time.Duration, error) {
+	if !k.Exist(key) {
+		return 0, notExist(key)
+	}
+	raw := k[key]
+
+	switch value := raw.(type) {
+	case time.Duration:
+		return value, nil
+	case string:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, typeMismatch(key, raw, "duration", err)
+		}
+		return duration, nil
+	}
+
+	nanoseconds, err := k.Int64Value(key)
+	if err != nil {
+		return 0, fmt.Errorf("k.Int64Value: %w", err)
+	}
+
+	return time.Duration(nanoseconds), nil
+}
+
+// TimeValue returns the parameter as a time.
+// The string must be in RFC 3339 format, the number is the unix time in seconds.
+func (k KeyValue) TimeValue(key string) (time.Time, error) {
+	if !k.Exist(key) {
+		return time.Time{}, notExist(key)
+	}
+	raw := k[key]
+
+	switch value := raw.(type) {
+	case time.Time:
+		return value, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, typeMismatch(key, raw, "time", err)
+		}
+		return parsed, nil
+	}
+
+	seconds, err := k.Int64Value(key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("k.Int64Value: %w", err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// BytesValue returns the parameter as the bytes.
+// The string is decoded from the base64, that's how json serializes the bytes.
+func (k KeyValue) BytesValue(key string) ([]byte, error) {
+	if !k.Exist(key) {
+		return nil, notExist(key)
+	}
+	raw := k[key]
+	if raw == nil {
+		return nil, nilValue(key)
+	}
+
+	switch value := raw.(type) {
+	case []byte:
+		return value, nil
+	case string:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, typeMismatch(key, raw, "bytes", err)
+		}
+		return decoded, nil
+	}
+
+	return nil, typeMismatch(key, raw, "bytes", nil)
+}
+
+// BigFloatValue returns the parameter as the arbitrary precision float.
+// Use this if the float doesn't fit into 64 bits.
+func (k KeyValue) BigFloatValue(key string) (*big.Float, error) {
+	if !k.Exist(key) {
+		return nil, notExist(key)
+	}
+	raw := k[key]
+	if raw == nil {
+		return nil, nilValue(key)
+	}
+
+	var number string
+	switch value := raw.(type) {
+	case *big.Float:
+		return new(big.Float).Copy(value), nil
+	case json.Number:
+		// the json number is parsed as it is, without rounding to float64
+		number = string(value)
+	case string:
+		number = value
+	default:
+		canonical, ok := canonicalNumber(raw)
+		if !ok {
+			return nil, typeMismatch(key, raw, "number", nil)
+		}
+		number = canonical
+	}
+
+	// each decimal digit takes less than 4 bits
+	precision := uint(len(number)) * 4
+	if precision < 64 {
+		precision = 64
+	}
+	parsed, _, err := big.ParseFloat(number, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return nil, typeMismatch(key, raw, "big float", err)
+	}
+
+	return parsed, nil
+}
+
+// Uint64sValue returns the list of uint64.
+// The elements are converted by the Uint64Value rules.
+func (k KeyValue) Uint64sValue(key string) ([]uint64, error) {
+	if !k.Exist(key) {
+		return nil, notExist(key)
+	}
+	raw := k[key]
+	if raw == nil {
+		return nil, nilValue(key)
+	}
+	if readyList, ok := raw.([]uint64); ok {
+		return readyList, nil
+	}
+
+	values, ok := toList(raw)
+	if !ok {
+		return nil, typeMismatch(key, raw, "number list", nil)
+	}
+
+	list := make([]uint64, len(values))
+	for i, rawValue := range values {
+		v, err := KeyValue{key: rawValue}.Uint64Value(key)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s[%d]: %w", key, i, err)
+		}
+
+		list[i] = v
+	}
+
+	return list, nil
+}
+
+// Float64sValue returns the list of float64.
+// The elements are converted by the Float64Value rules.
+func (k KeyValue) Float64sValue(key string) ([]float64, error) {
+	if !k.Exist(key) {
+		return nil, notExist(key)
+	}
+	raw := k[key]
+	if raw == nil {
+		return nil, nilValue(key)
+	}
+	if readyList, ok := raw.([]float64); ok {
+		return readyList, nil
+	}
+
+	values, ok := toList(raw)
+	if !ok {
+		return nil, typeMismatch(key, raw, "number list", nil)
+	}
+
+	list := make([]float64, len(values))
+	for i, rawValue := range values {
+		v, err := KeyValue{key: rawValue}.Float64Value(key)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s[%d]: %w", key, i, err)
+		}
+
+		list[i] = v
+	}
+
+	return list, nil
+}
+
+// The Or functions return the parameter converted by the getter with the same name,
+// or the default value if the parameter doesn't exist or can not be converted.
+// They never fail.
+
+// Uint64Or returns the Uint64Value or def
+func (k KeyValue) Uint64Or(key string, def uint64) uint64 {
+	value, err := k.Uint64Value(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// Int64Or returns the Int64Value or def
+func (k KeyValue) Int64Or(key string, def int64) int64 {
+	value, err := k.Int64Value(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// Uint32Or returns the Uint32Value or def
+func (k KeyValue) Uint32Or(key string, def uint32) uint32 {
+	value, err := k.Uint32Value(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// Int32Or returns the Int32Value or def
+func (k KeyValue) Int32Or(key string, def int32) int32 {
+	value, err := k.Int32Value(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// Float64Or returns the Float64Value or def
+func (k KeyValue) Float64Or(key string, def float64) float64 {
+	value, err := k.Float64Value(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// BoolOr returns the BoolValue or def
+func (k KeyValue) BoolOr(key string, def bool) bool {
+	value, err := k.BoolValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// StringOr returns the StringValue or def
+func (k KeyValue) StringOr(key string, def string) string {
+	value, err := k.StringValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// StringsOr returns the StringsValue or def
+func (k KeyValue) StringsOr(key string, def []string) []string {
+	value, err := k.StringsValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// Uint64sOr returns the Uint64sValue or def
+func (k KeyValue) Uint64sOr(key string, def []uint64) []uint64 {
+	value, err := k.Uint64sValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// Float64sOr returns the Float64sValue or def
+func (k KeyValue) Float64sOr(key string, def []float64) []float64 {
+	value, err := k.Float64sValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// BigIntOr returns the BigIntValue or def
+func (k KeyValue) BigIntOr(key string, def *big.Int) *big.Int {
+	value, err := k.BigIntValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// BigFloatOr returns the BigFloatValue or def
+func (k KeyValue) BigFloatOr(key string, def *big.Float) *big.Float {
+	value, err := k.BigFloatValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// DurationOr returns the DurationValue or def
+func (k KeyValue) DurationOr(key string, def time.Duration) time.Duration {
+	value, err := k.DurationValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// TimeOr returns the TimeValue or def
+func (k KeyValue) TimeOr(key string, def time.Time) time.Time {
+	value, err := k.TimeValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// BytesOr returns the BytesValue or def
+func (k KeyValue) BytesOr(key string, def []byte) []byte {
+	value, err := k.BytesValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// NestedOr returns the NestedValue or def
+func (k KeyValue) NestedOr(key string, def KeyValue) KeyValue {
+	value, err := k.NestedValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// NestedListOr returns the NestedListValue or def
+func (k KeyValue) NestedListOr(key string, def []KeyValue) []KeyValue {
+	value, err := k.NestedListValue(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
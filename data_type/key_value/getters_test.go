@@ -0,0 +1,190 @@
+package key_value
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestGettersSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+// SetupTest parses the parameters the same way as the messages are parsed
+func (suite *TestGettersSuite) SetupTest() {
+	kv, err := NewFromString(`{
+		"negative": -9007199254740993,
+		"positive": 42,
+		"fraction": 1.5,
+		"large": 5000000000,
+		"timeout": "1m30s",
+		"timeout_ns": 1000,
+		"created": "2023-05-01T10:00:00.5Z",
+		"created_unix": 1682935200,
+		"raw": "aGVsbG8=",
+		"pi": 3.14159265358979323846264338327950288,
+		"ids": [1, 2, 3],
+		"weights": [0.5, 1, "2.5"],
+		"name": "sds",
+		"nested": {"a": {"b": -1}}
+	}`)
+	suite.Require().NoError(err)
+	suite.kv = kv
+}
+
+func (suite *TestGettersSuite) TestSigned() {
+	// the negative number doesn't lose the precision
+	negative, err := suite.kv.Int64Value("negative")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-9007199254740993), negative)
+
+	positive, err := suite.kv.Int32Value("positive")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int32(42), positive)
+
+	_, err = suite.kv.Int64Value("fraction")
+	suite.Require().Error(err)
+	_, err = suite.kv.Int32Value("large")
+	suite.Require().Error(err)
+	_, err = suite.kv.Int64Value("name")
+	suite.Require().Error(err)
+	_, err = suite.kv.Int64Value("not_exist")
+	suite.Require().Error(err)
+
+	// the go types are accepted as well
+	kv := KeyValue{"int": -5, "float": float64(-7), "string": "-3"}
+	value, err := kv.Int64Value("int")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-5), value)
+	value, err = kv.Int64Value("float")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-7), value)
+	value, err = kv.Int64Value("string")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-3), value)
+
+	nested, err := suite.kv.Int64At("nested.a.b")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(-1), nested)
+}
+
+func (suite *TestGettersSuite) TestUnsigned() {
+	positive, err := suite.kv.Uint32Value("positive")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint32(42), positive)
+
+	_, err = suite.kv.Uint32Value("large")
+	suite.Require().Error(err)
+	_, err = suite.kv.Uint32Value("negative")
+	suite.Require().Error(err)
+}
+
+func (suite *TestGettersSuite) TestTime() {
+	timeout, err := suite.kv.DurationValue("timeout")
+	suite.Require().NoError(err)
+	suite.Require().Equal(90*time.Second, timeout)
+
+	timeout, err = suite.kv.DurationValue("timeout_ns")
+	suite.Require().NoError(err)
+	suite.Require().Equal(time.Microsecond, timeout)
+
+	_, err = suite.kv.DurationValue("name")
+	suite.Require().Error(err)
+
+	created, err := suite.kv.TimeValue("created")
+	suite.Require().NoError(err)
+	suite.Require().Equal(time.Date(2023, 5, 1, 10, 0, 0, 500000000, time.UTC), created.UTC())
+
+	created, err = suite.kv.TimeValue("created_unix")
+	suite.Require().NoError(err)
+	suite.Require().Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), created)
+
+	_, err = suite.kv.TimeValue("name")
+	suite.Require().Error(err)
+	_, err = suite.kv.TimeValue("fraction")
+	suite.Require().Error(err)
+}
+
+func (suite *TestGettersSuite) TestBytes() {
+	raw, err := suite.kv.BytesValue("raw")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte("hello"), raw)
+
+	raw, err = KeyValue{"raw": []byte{1, 2}}.BytesValue("raw")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte{1, 2}, raw)
+
+	_, err = suite.kv.BytesValue("name")
+	suite.Require().Error(err)
+	_, err = suite.kv.BytesValue("positive")
+	suite.Require().Error(err)
+}
+
+func (suite *TestGettersSuite) TestBigFloat() {
+	pi, err := suite.kv.BigFloatValue("pi")
+	suite.Require().NoError(err)
+	// more digits than float64 could keep
+	suite.Require().Equal("3.14159265358979323846", pi.Text('f', 20))
+
+	negative, err := suite.kv.BigFloatValue("negative")
+	suite.Require().NoError(err)
+	suite.Require().Equal("-9007199254740993", negative.Text('f', 0))
+
+	value, err := KeyValue{"value": uint64(7)}.BigFloatValue("value")
+	suite.Require().NoError(err)
+	suite.Require().Zero(value.Cmp(big.NewFloat(7)))
+
+	_, err = suite.kv.BigFloatValue("name")
+	suite.Require().Error(err)
+}
+
+func (suite *TestGettersSuite) TestLists() {
+	ids, err := suite.kv.Uint64sValue("ids")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]uint64{1, 2, 3}, ids)
+
+	weights, err := suite.kv.Float64sValue("weights")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]float64{0.5, 1, 2.5}, weights)
+
+	_, err = suite.kv.Uint64sValue("weights")
+	suite.Require().Error(err)
+	_, err = suite.kv.Uint64sValue("name")
+	suite.Require().Error(err)
+	_, err = suite.kv.Float64sValue("not_exist")
+	suite.Require().Error(err)
+}
+
+func (suite *TestGettersSuite) TestOr() {
+	suite.Require().Equal(uint64(42), suite.kv.Uint64Or("positive", 1))
+	suite.Require().Equal(uint64(1), suite.kv.Uint64Or("not_exist", 1))
+	suite.Require().Equal(int64(-1), suite.kv.Int64Or("name", -1))
+	suite.Require().Equal(uint32(1), suite.kv.Uint32Or("large", 1))
+	suite.Require().Equal(int32(42), suite.kv.Int32Or("positive", 1))
+	suite.Require().Equal(1.5, suite.kv.Float64Or("fraction", 0))
+	suite.Require().True(suite.kv.BoolOr("name", true))
+	suite.Require().Equal("sds", suite.kv.StringOr("name", "default"))
+	suite.Require().Equal("default", suite.kv.StringOr("positive", "default"))
+	suite.Require().Equal([]string{"a"}, suite.kv.StringsOr("ids", []string{"a"}))
+	suite.Require().Equal([]uint64{1, 2, 3}, suite.kv.Uint64sOr("ids", nil))
+	suite.Require().Nil(suite.kv.Float64sOr("name", nil))
+	suite.Require().Equal(big.NewInt(42), suite.kv.BigIntOr("positive", nil))
+	suite.Require().Nil(suite.kv.BigFloatOr("name", nil))
+	suite.Require().Equal(time.Second, suite.kv.DurationOr("name", time.Second))
+	suite.Require().True(suite.kv.TimeOr("not_exist", time.Time{}).IsZero())
+	suite.Require().Equal([]byte("hello"), suite.kv.BytesOr("raw", nil))
+	suite.Require().Equal(New(), suite.kv.NestedOr("name", New()))
+	suite.Require().Empty(suite.kv.NestedListOr("name", nil))
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestGetters(t *testing.T) {
+	suite.Run(t, new(TestGettersSuite))
+}
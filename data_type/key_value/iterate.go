@@ -0,0 +1,368 @@
+package key_value
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Delete removes the key. Nothing happens if the key doesn't exist.
+func (k KeyValue) Delete(key string) KeyValue {
+	delete(k, key)
+
+	return k
+}
+
+// Len returns the amount of the top level keys
+func (k KeyValue) Len() int {
+	return len(k)
+}
+
+// Keys returns the top level keys in random order.
+// Use SortedKeys for the stable order.
+func (k KeyValue) Keys() []string {
+	keys := make([]string, 0, len(k))
+	for key := range k {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// SortedKeys returns the top level keys in the ascending order
+func (k KeyValue) SortedKeys() []string {
+	keys := k.Keys()
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Range calls f for each top level key in the ascending order.
+// Stops if f returns false.
+func (k KeyValue) Range(f func(key string, value interface{}) bool) {
+	for _, key := range k.SortedKeys() {
+		if !f(key, k[key]) {
+			return
+		}
+	}
+}
+
+// Filter returns a new KeyValue with the elements for which f returns true.
+// The values are copied, so the returned KeyValue doesn't share the nested values with k.
+func (k KeyValue) Filter(f func(key string, value interface{}) bool) KeyValue {
+	filtered := make(KeyValue, len(k))
+	for key, value := range k {
+		if f(key, value) {
+			filtered[key] = deepCopy(value)
+		}
+	}
+
+	return filtered
+}
+
+// MapValues returns a new KeyValue where the values are replaced by the values returned by f.
+// The values returned by f can not be nil.
+func (k KeyValue) MapValues(f func(key string, value interface{}) (interface{}, error)) (KeyValue, error) {
+	mapped := make(KeyValue, len(k))
+	for _, key := range k.SortedKeys() {
+		value, err := f(key, k[key])
+		if err != nil {
+			return nil, fmt.Errorf("f(%s): %w", key, err)
+		}
+		if err := noNil(value); err != nil {
+			return nil, fmt.Errorf("f(%s): %w", key, err)
+		}
+		mapped[key] = value
+	}
+
+	return mapped, nil
+}
+
+// Pick returns a new KeyValue with the given top level keys only.
+// The missing keys are skipped.
+// The values are copied, so the returned KeyValue doesn't share the nested values with k.
+func (k KeyValue) Pick(keys ...string) KeyValue {
+	picked := make(KeyValue, len(keys))
+	for _, key := range keys {
+		if value, ok := k[key]; ok {
+			picked[key] = deepCopy(value)
+		}
+	}
+
+	return picked
+}
+
+// Omit returns a new KeyValue without the given top level keys.
+// The values are copied, so the returned KeyValue doesn't share the nested values with k.
+func (k KeyValue) Omit(keys ...string) KeyValue {
+	omitted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		omitted[key] = struct{}{}
+	}
+
+	return k.Filter(func(key string, _ interface{}) bool {
+		_, ok := omitted[key]
+		return !ok
+	})
+}
+
+// PickAt returns a new KeyValue with the values by the given dotted paths only.
+// The nested maps keep the picked keys, and the lists keep the picked elements in their order:
+//
+//	kv.PickAt("user.name", "items.0.qty") // {"user":{"name":...},"items":[{"qty":...}]}
+//
+// The missing paths are skipped.
+// The values are copied, so the returned KeyValue doesn't share the nested values with k.
+func (k KeyValue) PickAt(paths ...string) (KeyValue, error) {
+	segmentsList := make([][]string, len(paths))
+	for i, path := range paths {
+		segments, err := splitPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("splitPath: %w", err)
+		}
+		segmentsList[i] = segments
+	}
+
+	picked, ok := pickPaths(k, segmentsList).(KeyValue)
+	if !ok {
+		return New(), nil
+	}
+	return picked, nil
+}
+
+// pickPaths returns the copy of the node with the values by the paths.
+// Returns nil if the node has none of them.
+func pickPaths(node interface{}, paths [][]string) interface{} {
+	// the first segments in their order, and the rest of the paths by them
+	segments := make([]string, 0, len(paths))
+	rests := make(map[string][][]string, len(paths))
+	for _, path := range paths {
+		if len(path) == 0 {
+			return deepCopy(node)
+		}
+		if _, ok := rests[path[0]]; !ok {
+			segments = append(segments, path[0])
+		}
+		rests[path[0]] = append(rests[path[0]], path[1:])
+	}
+
+	if nested, ok := toKeyValue(node); ok {
+		picked := make(KeyValue, len(segments))
+		for _, segment := range segments {
+			value, ok := nested[segment]
+			if !ok {
+				continue
+			}
+			if pickedValue := pickPaths(value, rests[segment]); pickedValue != nil {
+				picked[segment] = pickedValue
+			}
+		}
+		if len(picked) == 0 {
+			return nil
+		}
+		if _, ok := node.(map[string]interface{}); ok {
+			return map[string]interface{}(picked)
+		}
+		return picked
+	}
+
+	if list, ok := toList(node); ok {
+		indexes := make([]int, 0, len(segments))
+		indexRests := make(map[int][][]string, len(segments))
+		for _, segment := range segments {
+			index, err := listIndex(segment, len(list))
+			if err != nil {
+				continue
+			}
+			if _, ok := indexRests[index]; !ok {
+				indexes = append(indexes, index)
+			}
+			indexRests[index] = append(indexRests[index], rests[segment]...)
+		}
+		sort.Ints(indexes)
+
+		picked := make([]interface{}, 0, len(indexes))
+		for _, index := range indexes {
+			if pickedValue := pickPaths(list[index], indexRests[index]); pickedValue != nil {
+				picked = append(picked, pickedValue)
+			}
+		}
+		if len(picked) == 0 {
+			return nil
+		}
+		return picked
+	}
+
+	return nil
+}
+
+// OmitAt returns a new KeyValue without the values by the given dotted paths.
+// The list indexes refer to the elements of k,
+// so "items.0" and "items.1" remove the first two elements.
+// The missing paths are skipped.
+// The values are copied, so the returned KeyValue doesn't share the nested values with k.
+func (k KeyValue) OmitAt(paths ...string) (KeyValue, error) {
+	segmentsList := make([][]string, len(paths))
+	for i, path := range paths {
+		segments, err := splitPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("splitPath: %w", err)
+		}
+		segmentsList[i] = segments
+	}
+
+	// the later list elements are removed first, so the indexes of the former are not shifted
+	sort.Slice(segmentsList, func(i, j int) bool {
+		return comparePaths(segmentsList[i], segmentsList[j]) > 0
+	})
+
+	omitted := k.deepCopy()
+	for _, segments := range segmentsList {
+		path := strings.Join(segments, PathSeparator)
+		if !omitted.ExistAt(path) {
+			continue
+		}
+		if err := omitted.DeleteAt(path); err != nil {
+			return nil, fmt.Errorf("DeleteAt('%s'): %w", path, err)
+		}
+	}
+
+	return omitted, nil
+}
+
+// comparePaths compares the paths segment by segment,
+// where the list indexes are compared as the numbers.
+// The path is less than the paths it's the prefix of.
+func comparePaths(a []string, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+
+		aIndex, aErr := strconv.Atoi(a[i])
+		bIndex, bErr := strconv.Atoi(b[i])
+		if aErr == nil && bErr == nil {
+			if aIndex < bIndex {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a[i], b[i])
+	}
+
+	return len(a) - len(b)
+}
+
+// Flatten returns the KeyValue where the nested values are set by their dotted paths:
+//
+//	{"user":{"name":"sds"},"tags":["a"]} // {"user.name":"sds","tags.0":"a"}
+//
+// The empty maps and lists are kept as the values.
+// The keys can not be empty or contain PathSeparator, as they can not be the segments of the path.
+func (k KeyValue) Flatten() (KeyValue, error) {
+	flat := New()
+	if err := flatten(flat, "", k); err != nil {
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+func flatten(flat KeyValue, prefix string, value interface{}) error {
+	if nested, ok := toKeyValue(value); ok && len(nested) > 0 {
+		for key, nestedValue := range nested {
+			if len(key) == 0 || strings.Contains(key, PathSeparator) {
+				return fmt.Errorf("key '%s' in '%s' can not be the path segment", key, prefix)
+			}
+			if err := flatten(flat, joinPath(prefix, key), nestedValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if list, ok := toList(value); ok && len(list) > 0 {
+		for i, element := range list {
+			if err := flatten(flat, joinPath(prefix, strconv.Itoa(i)), element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(prefix) > 0 {
+		flat[prefix] = deepCopy(value)
+	}
+	return nil
+}
+
+// Unflatten is the reverse of Flatten.
+// The dotted keys are set as the nested values,
+// and the nested maps with the keys from "0" to the length - 1 are converted into the lists.
+// Returns an error if the keys conflict, like "user" and "user.name".
+func (k KeyValue) Unflatten() (KeyValue, error) {
+	tree := make(flatNode, len(k))
+	for _, key := range k.SortedKeys() {
+		segments, err := splitPath(key)
+		if err != nil {
+			return nil, fmt.Errorf("splitPath: %w", err)
+		}
+
+		node := tree
+		for i, segment := range segments[:len(segments)-1] {
+			next, ok := node[segment]
+			if !ok {
+				next = make(flatNode)
+				node[segment] = next
+			}
+			nested, ok := next.(flatNode)
+			if !ok {
+				return nil, fmt.Errorf("'%s' conflicts with '%s'", key, strings.Join(segments[:i+1], PathSeparator))
+			}
+			node = nested
+		}
+
+		last := segments[len(segments)-1]
+		if _, ok := node[last]; ok {
+			return nil, fmt.Errorf("'%s' conflicts with the nested keys", key)
+		}
+		node[last] = deepCopy(k[key])
+	}
+
+	unflattened := make(KeyValue, len(tree))
+	for key, value := range tree {
+		unflattened[key] = unflattenNode(value)
+	}
+
+	return unflattened, nil
+}
+
+// flatNode is the map created by Unflatten.
+// The maps of the values have the other type, so they are not merged with the dotted keys.
+type flatNode map[string]interface{}
+
+// unflattenNode converts the flatNode into the map,
+// or into the list if its keys are from "0" to the length - 1
+func unflattenNode(value interface{}) interface{} {
+	node, ok := value.(flatNode)
+	if !ok {
+		return value
+	}
+
+	nested := make(map[string]interface{}, len(node))
+	for key, nestedValue := range node {
+		nested[key] = unflattenNode(nestedValue)
+	}
+
+	list := make([]interface{}, len(nested))
+	for i := range list {
+		element, ok := nested[strconv.Itoa(i)]
+		if !ok {
+			return nested
+		}
+		list[i] = element
+	}
+
+	return list
+}
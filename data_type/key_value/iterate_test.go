@@ -0,0 +1,177 @@
+package key_value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestIterateSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+func (suite *TestIterateSuite) SetupTest() {
+	kv, err := NewFromString(`{"name":"sds","password":"secret","amount":5,
+		"user":{"name":"a","token":"t"},"items":[{"qty":1,"price":2},{"qty":3,"price":4}],"tags":["a","b"]}`)
+	suite.Require().NoError(err)
+	suite.kv = kv
+}
+
+func (suite *TestIterateSuite) TestKeys() {
+	suite.Require().Equal(6, suite.kv.Len())
+	suite.Require().Equal([]string{"amount", "items", "name", "password", "tags", "user"}, suite.kv.SortedKeys())
+	suite.Require().ElementsMatch(suite.kv.SortedKeys(), suite.kv.Keys())
+
+	suite.kv.Delete("password").Delete("not_exist")
+	suite.Require().Equal(5, suite.kv.Len())
+	suite.Require().False(suite.kv.Exist("password"))
+
+	suite.Require().Empty(New().Keys())
+}
+
+func (suite *TestIterateSuite) TestRange() {
+	keys := make([]string, 0)
+	suite.kv.Range(func(key string, _ interface{}) bool {
+		keys = append(keys, key)
+		return key != "name"
+	})
+	suite.Require().Equal([]string{"amount", "items", "name"}, keys)
+}
+
+func (suite *TestIterateSuite) TestFilterAndMap() {
+	texts := suite.kv.Filter(func(_ string, value interface{}) bool {
+		_, ok := value.(string)
+		return ok
+	})
+	suite.Require().Equal(KeyValue{"name": "sds", "password": "secret"}, texts)
+
+	// the filtered values are copied
+	nested := suite.kv.Filter(func(key string, _ interface{}) bool {
+		return key == "user"
+	})
+	suite.Require().NoError(nested.SetAt("user.name", "changed"))
+	suite.Require().Equal("a", suite.kv["user"].(map[string]interface{})["name"])
+
+	mapped, err := suite.kv.Pick("name", "amount").MapValues(func(key string, value interface{}) (interface{}, error) {
+		return fmt.Sprintf("%s=%v", key, value), nil
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(KeyValue{"name": "name=sds", "amount": "amount=5"}, mapped)
+
+	_, err = suite.kv.MapValues(func(key string, value interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	suite.Require().ErrorIs(err, ErrNilValue)
+
+	_, err = suite.kv.MapValues(func(key string, value interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("failed")
+	})
+	suite.Require().Error(err)
+}
+
+func (suite *TestIterateSuite) TestPickAndOmit() {
+	picked := suite.kv.Pick("name", "user", "not_exist")
+	suite.Require().Equal([]string{"name", "user"}, picked.SortedKeys())
+
+	// the picked values are copied
+	suite.Require().NoError(picked.SetAt("user.name", "changed"))
+	suite.Require().Equal("a", suite.kv["user"].(map[string]interface{})["name"])
+
+	omitted := suite.kv.Omit("password", "user", "not_exist")
+	suite.Require().Equal([]string{"amount", "items", "name", "tags"}, omitted.SortedKeys())
+	suite.Require().True(suite.kv.Exist("password"))
+}
+
+func (suite *TestIterateSuite) TestPickAt() {
+	picked, err := suite.kv.PickAt("user.name", "items.1.qty", "items.0.qty", "tags.1", "amount.not_exist", "items.5")
+	suite.Require().NoError(err)
+
+	expected, err := NewFromString(`{"user":{"name":"a"},"items":[{"qty":1},{"qty":3}],"tags":["b"]}`)
+	suite.Require().NoError(err)
+	suite.Require().True(expected.Equal(picked), picked.String())
+
+	// the whole nested value
+	picked, err = suite.kv.PickAt("user", "user.name")
+	suite.Require().NoError(err)
+	suite.Require().True(suite.kv.Pick("user").Equal(picked))
+
+	picked, err = suite.kv.PickAt("not_exist")
+	suite.Require().NoError(err)
+	suite.Require().Empty(picked)
+
+	_, err = suite.kv.PickAt("user..name")
+	suite.Require().Error(err)
+}
+
+func (suite *TestIterateSuite) TestOmitAt() {
+	omitted, err := suite.kv.OmitAt("password", "user.token", "items.0.price", "items.1.price", "tags.0", "tags.1", "not.exist")
+	suite.Require().NoError(err)
+
+	expected, err := NewFromString(`{"name":"sds","amount":5,"user":{"name":"a"},"items":[{"qty":1},{"qty":3}],"tags":[]}`)
+	suite.Require().NoError(err)
+	suite.Require().True(expected.Equal(omitted), omitted.String())
+
+	// the original is not changed
+	suite.Require().True(suite.kv.ExistAt("user.token"))
+	suite.Require().True(suite.kv.ExistAt("items.1.price"))
+
+	// the indexes refer to the original list, regardless of the order
+	omitted, err = suite.kv.OmitAt("items.0", "items.1.qty")
+	suite.Require().NoError(err)
+	items, err := omitted.NestedListValue("items")
+	suite.Require().NoError(err)
+	suite.Require().Len(items, 1)
+	suite.Require().True(KeyValue{"price": 4}.Equal(items[0]))
+
+	_, err = suite.kv.OmitAt("")
+	suite.Require().Error(err)
+}
+
+func (suite *TestIterateSuite) TestFlatten() {
+	suite.kv.Set("empty", New()).Set("empty_list", []interface{}{})
+
+	flat, err := suite.kv.Flatten()
+	suite.Require().NoError(err)
+	suite.Require().Equal("a", flat["user.name"])
+	suite.Require().Equal("b", flat["tags.1"])
+	suite.Require().Equal(uint64(3), flat.Uint64Or("items.1.qty", 0))
+	suite.Require().Equal(New(), flat["empty"])
+	suite.Require().False(flat.Exist("user"))
+
+	unflattened, err := flat.Unflatten()
+	suite.Require().NoError(err)
+	suite.Require().True(suite.kv.Equal(unflattened), unflattened.String())
+
+	// the keys that can not be the path segments
+	_, err = KeyValue{"user": KeyValue{"first.name": "a"}}.Flatten()
+	suite.Require().Error(err)
+	_, err = KeyValue{"": 1}.Flatten()
+	suite.Require().Error(err)
+}
+
+func (suite *TestIterateSuite) TestUnflatten() {
+	unflattened, err := KeyValue{"list.0": 1, "list.1": 2, "map.0": 1, "map.2": 2, "a.b.c": true}.Unflatten()
+	suite.Require().NoError(err)
+	suite.Require().Equal([]interface{}{1, 2}, unflattened["list"])
+	suite.Require().Equal(map[string]interface{}{"0": 1, "2": 2}, unflattened["map"])
+	suite.Require().True(unflattened.ExistAt("a.b.c"))
+
+	// the map values are not merged with the dotted keys
+	_, err = KeyValue{"user": KeyValue{"name": "a"}, "user.token": "t"}.Unflatten()
+	suite.Require().Error(err)
+	_, err = KeyValue{"user": "a", "user.token": "t"}.Unflatten()
+	suite.Require().Error(err)
+	_, err = KeyValue{"user..token": "t"}.Unflatten()
+	suite.Require().Error(err)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestIterate(t *testing.T) {
+	suite.Run(t, new(TestIterateSuite))
+}
@@ -1,14 +1,20 @@
 // Package key_value defines the custom map and its additional functions.
 //
-// The package defines two different data types:
+// The package defines the following data types:
 //   - [KeyValue] is the map where the kv is a string, and the value could be anything.
 //     It defines additional functions that return the value converted to the desired type.
 //   - [List] is the list of elements but based on the map.
 //     For the user, the list acts as the array.
 //     However, internally it uses a map for optimization.
+//     The list remembers the order in which the elements were added.
+//   - [TypedList] is the generic List, the key and value types are checked at compile time.
+//   - [Cache] is the List that evicts the elements by LRU or LFU policy when it's full.
+//   - [SyncList] is the List that is safe to share between the goroutines.
+//   - [Schema] validates the values of the KeyValue.
 package key_value
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -73,7 +79,7 @@ func NewFromInterface(i interface{}) (KeyValue, error) {
 func (k KeyValue) noNilValue() error {
 	for key, value := range k {
 		if value == nil {
-			return fmt.Errorf("kv %s is nil", key)
+			return nilValue(key)
 		}
 
 		nestedKv, ok := value.(KeyValue)
@@ -102,80 +108,69 @@ func (k KeyValue) noNilValue() error {
 	return nil
 }
 
-// It sets the numbers in a string format.
-// The string format for the number means a json number
-func (k KeyValue) setNumber() {
-	for key, value := range k {
-		if value == nil {
-			continue
-		}
-
-		// even if it's a number wrapped as a string,
-		// we won't convert it.
-		_, ok := value.(string)
-		if ok {
-			continue
-		}
-
-		_, ok = value.(json.Number)
-		if ok {
-			continue
-		}
-
-		bigNum, err := k.BigIntValue(key)
-		if err == nil {
-			delete(k, key)
-
-			jsonNumber := json.Number(bigNum.String())
-			k.Set(key, jsonNumber)
-			continue
-		}
-
-		floatNum, err := k.Float64Value(key)
-		if err == nil {
-			delete(k, key)
+// normalize returns the copy of the value where the go numbers, like uint64 or *big.Int, are json.Number.
+// The json.Number is kept as it is, so the decoded numbers are serialized without losing the digits.
+// The nested maps and lists are normalized as well.
+// The value itself is not changed, so it's safe to serialize the shared KeyValue.
+func normalize(value interface{}) interface{} {
+	return convertNumbers(value, false)
+}
 
-			jsonNumber := json.Number(strconv.FormatFloat(floatNum, 'G', -1, 64))
-			k.Set(key, jsonNumber)
-			continue
+// convertNumbers returns the copy of the value with the numbers converted into json.Number.
+// If canonical is true, then all numbers, including json.Number, are in the format of comparableNumber.
+func convertNumbers(value interface{}, canonical bool) interface{} {
+	if canonical {
+		if number, ok := comparableNumber(value); ok {
+			return json.Number(number)
 		}
-
-		num, err := k.Uint64Value(key)
-		if err == nil {
-			delete(k, key)
-
-			jsonNumber := json.Number(strconv.FormatUint(num, 10))
-			k.Set(key, jsonNumber)
-			continue
+	} else if number, ok := value.(json.Number); ok {
+		return number
+	} else if number, ok := canonicalNumber(value); ok {
+		return json.Number(number)
+	}
+
+	switch typed := value.(type) {
+	case KeyValue:
+		return typed.convertNumbers(canonical)
+	case map[string]interface{}:
+		return map[string]interface{}(KeyValue(typed).convertNumbers(canonical))
+	case []interface{}:
+		list := make([]interface{}, len(typed))
+		for i, element := range typed {
+			list[i] = convertNumbers(element, canonical)
 		}
-
-		nestedKv, ok := value.(KeyValue)
-		if ok {
-			nestedKv.setNumber()
-
-			delete(k, key)
-			k.Set(key, nestedKv)
-			continue
+		return list
+	case []KeyValue:
+		list := make([]KeyValue, len(typed))
+		for i, element := range typed {
+			list[i] = element.convertNumbers(canonical)
 		}
+		return list
+	}
 
-		nestedMap, ok := value.(map[string]interface{})
+	return deepCopy(value)
+}
 
-		if ok {
-			nestedKv = nestedMap
-			// ToMap will call setNumber()
-			nestedMap = nestedKv.Map()
+// normalized returns the copy of k with the normalized values
+func (k KeyValue) normalized() KeyValue {
+	return k.convertNumbers(false)
+}
 
-			delete(k, key)
-			k.Set(key, nestedMap)
-			continue
-		}
+// convertNumbers returns the copy of k with the converted numbers
+func (k KeyValue) convertNumbers(canonical bool) KeyValue {
+	converted := make(KeyValue, len(k))
+	for key, value := range k {
+		converted[key] = convertNumbers(value, canonical)
 	}
+
+	return converted
 }
 
-// Map converts the k to golang's map
+// Map converts the k to golang's map.
+// The go numbers are converted to json.Number, the json.Number values are kept as they are.
+// The returned map is a copy, k is not changed.
 func (k KeyValue) Map() map[string]interface{} {
-	k.setNumber()
-	return k
+	return k.normalized()
 }
 
 // MapString returns the map with the string values only
@@ -195,15 +190,15 @@ func (k KeyValue) MapString() map[string]string {
 	return data
 }
 
-// Bytes serialize k into the series of bytes
+// Bytes serialize k into the series of bytes.
+// The keys are sorted, k is not changed.
 func (k KeyValue) Bytes() ([]byte, error) {
 	err := k.noNilValue()
 	if err != nil {
 		return []byte{}, fmt.Errorf("nil value: %w", err)
 	}
-	k.setNumber()
 
-	bytes, err := json.Marshal(k)
+	bytes, err := json.Marshal(k.normalized())
 	if err != nil {
 		return []byte{}, fmt.Errorf("json.serialize: '%w'", err)
 	}
@@ -211,6 +206,28 @@ func (k KeyValue) Bytes() ([]byte, error) {
 	return bytes, nil
 }
 
+// Canonical serializes k into the form that is suitable for hashing and signing.
+// The same KeyValue always gives the same bytes:
+// the keys are sorted, there are no spaces,
+// the numbers are written in the shortest decimal format regardless of their go type,
+// the decimals keep all their digits, the integers from 1e21 are written with the exponent like float64 is,
+// and the strings are not HTML escaped.
+func (k KeyValue) Canonical() ([]byte, error) {
+	if err := noNil(k); err != nil {
+		return []byte{}, fmt.Errorf("noNil: %w", err)
+	}
+
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(k.convertNumbers(true)); err != nil {
+		return []byte{}, fmt.Errorf("json.serialize: '%w'", err)
+	}
+
+	// the encoder ends the value with a new line
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
 // Returns the serialized kv-value as a string
 func (k KeyValue) String() string {
 	bytes, err := k.Bytes()
@@ -253,11 +270,11 @@ func (k KeyValue) Exist(key string) (exists bool) {
 // Uint64Value returns the parameter as an uint64
 func (k KeyValue) Uint64Value(key string) (uint64, error) {
 	if !k.Exist(key) {
-		return 0, fmt.Errorf("not exist")
+		return 0, notExist(key)
 	}
 	raw := k[key]
 	if raw == nil {
-		return 0, fmt.Errorf("kv %s is nil", key)
+		return 0, nilValue(key)
 	}
 
 	pureValue, ok := raw.(uint64)
@@ -273,18 +290,27 @@ func (k KeyValue) Uint64Value(key string) (uint64, error) {
 	if ok {
 		number, err := strconv.ParseUint(string(jsonValue), 10, 64)
 		if err != nil {
-			return 0, fmt.Errorf("strconv.ParseUint(%v (type %T) as json number %v): '%w'", raw, raw, jsonValue, err)
+			return 0, typeMismatch(key, raw, "uint64", err)
+		}
+		return number, nil
+	}
+
+	// the other go numbers, like int64 or *big.Int
+	if canonical, ok := canonicalNumber(raw); ok {
+		number, err := strconv.ParseUint(canonical, 10, 64)
+		if err != nil {
+			return 0, typeMismatch(key, raw, "uint64", err)
 		}
 		return number, nil
 	}
 
 	stringValue, ok := raw.(string)
 	if !ok {
-		return 0, fmt.Errorf("'%s' parameter type %T, can not convert to number", key, raw)
+		return 0, typeMismatch(key, raw, "number", nil)
 	}
 	number, err := strconv.ParseUint(stringValue, 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("strconv.ParseUint string %v (original: %v): '%w'", stringValue, raw, err)
+		return 0, typeMismatch(key, raw, "uint64", err)
 	}
 
 	return number, nil
@@ -293,12 +319,12 @@ func (k KeyValue) Uint64Value(key string) (uint64, error) {
 // Float64Value extracts the float number
 func (k KeyValue) Float64Value(key string) (float64, error) {
 	if !k.Exist(key) {
-		return 0, fmt.Errorf("not exist")
+		return 0, notExist(key)
 
 	}
 	raw := k[key]
 	if raw == nil {
-		return 0, fmt.Errorf("kv %s is nil", key)
+		return 0, nilValue(key)
 	}
 
 	pureValue, ok := raw.(float64)
@@ -309,17 +335,25 @@ func (k KeyValue) Float64Value(key string) (float64, error) {
 	if ok {
 		v, err := value.Float64()
 		if err != nil {
-			return 0, fmt.Errorf("json.Number.Float64() of %v (original: %v): '%w'", value, raw, err)
+			return 0, typeMismatch(key, raw, "float64", err)
 		}
 		return v, nil
 	}
+	// the other go numbers, like uint64 or *big.Int
+	if canonical, ok := canonicalNumber(raw); ok {
+		number, err := strconv.ParseFloat(canonical, 64)
+		if err != nil {
+			return 0, typeMismatch(key, raw, "float64", err)
+		}
+		return number, nil
+	}
 	stringValue, ok := raw.(string)
 	if !ok {
-		return 0, fmt.Errorf("'%s' parameter type %T, can not convert to number", key, raw)
+		return 0, typeMismatch(key, raw, "number", nil)
 	}
 	number, err := strconv.ParseFloat(stringValue, 64)
 	if err != nil {
-		return 0, fmt.Errorf("strconv.ParseUint string %v (original: %v): '%w'", stringValue, raw, err)
+		return 0, typeMismatch(key, raw, "float64", err)
 	}
 
 	return number, nil
@@ -328,11 +362,11 @@ func (k KeyValue) Float64Value(key string) (float64, error) {
 // BoolValue extracts the value as boolean
 func (k KeyValue) BoolValue(key string) (bool, error) {
 	if !k.Exist(key) {
-		return false, fmt.Errorf("not exist")
+		return false, notExist(key)
 	}
 	raw := k[key]
 	if raw == nil {
-		return false, fmt.Errorf("kv %s is nil", key)
+		return false, nilValue(key)
 	}
 
 	pureValue, ok := raw.(bool)
@@ -340,27 +374,33 @@ func (k KeyValue) BoolValue(key string) (bool, error) {
 		return pureValue, nil
 	}
 
-	return false, fmt.Errorf("'%s' parameter type %T, can not convert to boolean", key, raw)
+	return false, typeMismatch(key, raw, "boolean", nil)
 }
 
 // BigIntValue extracts the value as the parsed large number. Use this if the number size is more than 64 bits.
+// The number must be an integer.
 func (k KeyValue) BigIntValue(key string) (*big.Int, error) {
 	if !k.Exist(key) {
-		return nil, fmt.Errorf("not exist")
+		return nil, notExist(key)
 	}
 	raw := k[key]
 	if raw == nil {
-		return nil, fmt.Errorf("kv %s is nil", key)
+		return nil, nilValue(key)
 	}
 
 	value, ok := raw.(json.Number)
 	if !ok {
-		return nil, fmt.Errorf("json.Number: '%s' parameter type %T", key, raw)
+		// the other go integers, like uint64 or *big.Int
+		canonical, isNumber := canonicalNumber(raw)
+		if !isNumber {
+			return nil, typeMismatch(key, raw, "big number", nil)
+		}
+		value = json.Number(canonical)
 	}
 
 	number, ok := big.NewInt(0).SetString(string(value), 10)
 	if !ok {
-		return nil, fmt.Errorf("math.ParseBig256 failed to parse %s from '%s'", key, value)
+		return nil, typeMismatch(key, raw, "big number", fmt.Errorf("failed to parse '%s'", value))
 	}
 
 	return number, nil
@@ -369,16 +409,16 @@ func (k KeyValue) BigIntValue(key string) (*big.Int, error) {
 // StringValue returns the parameter as a string
 func (k KeyValue) StringValue(key string) (string, error) {
 	if !k.Exist(key) {
-		return "", fmt.Errorf("not exist")
+		return "", notExist(key)
 	}
 	raw := k[key]
 	if raw == nil {
-		return "", fmt.Errorf("kv %s is nil", key)
+		return "", nilValue(key)
 	}
 
 	value, ok := raw.(string)
 	if !ok {
-		return "", fmt.Errorf("%s parameter type %T, can not convert to string", key, raw)
+		return "", typeMismatch(key, raw, "string", nil)
 	}
 
 	return value, nil
@@ -387,15 +427,18 @@ func (k KeyValue) StringValue(key string) (string, error) {
 // StringsValue returns the list of strings
 func (k KeyValue) StringsValue(key string) ([]string, error) {
 	if !k.Exist(key) {
-		return nil, fmt.Errorf("not exist")
+		return nil, notExist(key)
 	}
 	raw := k[key]
+	if raw == nil {
+		return nil, nilValue(key)
+	}
 
 	values, ok := raw.([]interface{})
 	if !ok {
 		readyList, ok := raw.([]string)
 		if !ok {
-			return nil, fmt.Errorf("'%s' parameter type %T, can not convert to string list", key, raw)
+			return nil, typeMismatch(key, raw, "string list", nil)
 		} else {
 			return readyList, nil
 		}
@@ -405,7 +448,7 @@ func (k KeyValue) StringsValue(key string) ([]string, error) {
 	for i, rawValue := range values {
 		v, ok := rawValue.(string)
 		if !ok {
-			return nil, fmt.Errorf("parameter %s[%d] type is %T, can not convert to string %v", key, i, rawValue, rawValue)
+			return nil, typeMismatch(fmt.Sprintf("%s[%d]", key, i), rawValue, "string", nil)
 		}
 
 		list[i] = v
@@ -419,15 +462,18 @@ func (k KeyValue) StringsValue(key string) ([]string, error) {
 // []key_value.KeyValue
 func (k KeyValue) NestedListValue(key string) ([]KeyValue, error) {
 	if !k.Exist(key) {
-		return nil, fmt.Errorf("not exist")
+		return nil, notExist(key)
 	}
 	raw := k[key]
+	if raw == nil {
+		return nil, nilValue(key)
+	}
 
 	values, ok := raw.([]interface{})
 	if !ok {
 		readyList, ok := raw.([]KeyValue)
 		if !ok {
-			return nil, fmt.Errorf("'%s' parameter type %T, can not convert to kv-value list", key, raw)
+			return nil, typeMismatch(key, raw, "kv-value list", nil)
 		} else {
 			return readyList, nil
 		}
@@ -437,7 +483,7 @@ func (k KeyValue) NestedListValue(key string) ([]KeyValue, error) {
 	for i, rawValue := range values {
 		v, ok := rawValue.(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("parameter %s[%d] type is %T, can not convert to kv-value %v", key, i, rawValue, rawValue)
+			return nil, typeMismatch(fmt.Sprintf("%s[%d]", key, i), rawValue, "kv-value", nil)
 		}
 
 		list[i] = v
@@ -449,18 +495,18 @@ func (k KeyValue) NestedListValue(key string) ([]KeyValue, error) {
 // NestedValue returns the parameter as a KeyValue
 func (k KeyValue) NestedValue(key string) (KeyValue, error) {
 	if !k.Exist(key) {
-		return nil, fmt.Errorf("not exist")
+		return nil, notExist(key)
 	}
 	raw := k[key]
 	if raw == nil {
-		return nil, fmt.Errorf("kv %s is nil", key)
+		return nil, nilValue(key)
 	}
 
 	value, ok := raw.(KeyValue)
 	if ok {
 		err := value.noNilValue()
 		if err != nil {
-			return nil, fmt.Errorf("kv %s is nil", key)
+			return nil, nilValue(key)
 		}
 
 		return value, nil
@@ -468,13 +514,13 @@ func (k KeyValue) NestedValue(key string) (KeyValue, error) {
 
 	rawMap, ok := raw.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("'%s' parameter type %T, can not convert to kv-value", key, raw)
+		return nil, typeMismatch(key, raw, "kv-value", nil)
 	}
 
 	var nestedKv KeyValue = rawMap
 	err := nestedKv.noNilValue()
 	if err != nil {
-		return nil, fmt.Errorf("kv %s is nil", key)
+		return nil, nilValue(key)
 	}
 
 	return nestedKv, nil
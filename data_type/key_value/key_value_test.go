@@ -1,6 +1,8 @@
 package key_value
 
 import (
+	"math/big"
+	"sync"
 	"testing"
 
 	"encoding/json"
@@ -194,6 +196,106 @@ func (suite *TestKeyValueSuite) TestToInterface() {
 	suite.Require().Error(err)
 }
 
+func (suite *TestKeyValueSuite) TestNoMutation() {
+	kv := KeyValue{
+		"float":  float64(1.5),
+		"uint":   uint64(2),
+		"nested": KeyValue{"int": -3, "list": []interface{}{uint64(4), float64(5)}},
+	}
+
+	_ = kv.Map()
+	_, err := kv.Bytes()
+	suite.Require().NoError(err)
+
+	// the serialization keeps the original go types
+	suite.Require().Equal(float64(1.5), kv["float"])
+	suite.Require().Equal(uint64(2), kv["uint"])
+	suite.Require().Equal(-3, kv["nested"].(KeyValue)["int"])
+	suite.Require().Equal([]interface{}{uint64(4), float64(5)}, kv["nested"].(KeyValue)["list"])
+
+	// the map is a copy
+	converted := kv.Map()
+	converted["float"] = "changed"
+	suite.Require().Equal(float64(1.5), kv["float"])
+	suite.Require().Equal(json.Number("-3"), converted["nested"].(KeyValue)["int"])
+
+	// the shared kv is serialized concurrently, run it with -race
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			suite.Equal(`{"float":1.5,"nested":{"int":-3,"list":[4,5]},"uint":2}`, kv.String())
+		}()
+	}
+	wg.Wait()
+}
+
+func (suite *TestKeyValueSuite) TestPrecision() {
+	str := `{"n":{"p":1.50},"price":0.123456789012345678901,"supply":1e400}`
+	kv, err := NewFromString(str)
+	suite.Require().NoError(err)
+
+	// the decoded numbers are serialized as they are
+	bytes, err := kv.Bytes()
+	suite.Require().NoError(err)
+	suite.Require().Equal(str, string(bytes))
+	suite.Require().Equal(str, kv.String())
+	suite.Require().Equal(json.Number("0.123456789012345678901"), kv.Map()["price"])
+}
+
+func (suite *TestKeyValueSuite) TestCanonical() {
+	supply, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	suite.Require().True(ok)
+
+	a := KeyValue{
+		"b":      float64(5),
+		"a":      "<html>",
+		"supply": supply,
+		"nested": map[string]interface{}{"z": uint64(1), "y": []interface{}{1.0, "x"}},
+	}
+	b, err := NewFromString(`{"nested":{"y":[1,"x"],"z":1.0},"supply":123456789012345678901234567890,"a":"<html>","b":5e0}`)
+	suite.Require().NoError(err)
+
+	canonical, err := a.Canonical()
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{"a":"<html>","b":5,"nested":{"y":[1,"x"],"z":1},"supply":1.2345678901234567890123456789e+29}`, string(canonical))
+
+	// the same data in the different go types gives the same bytes
+	other, err := b.Canonical()
+	suite.Require().NoError(err)
+	suite.Require().Equal(canonical, other)
+
+	// the decimals are exact
+	decimals := map[string]string{
+		`0.1`:                      `0.1`,
+		`0.1000000000000000001`:    `0.1000000000000000001`,
+		`1.50`:                     `1.5`,
+		`-0.00012`:                 `-0.00012`,
+		`0.000012`:                 `1.2e-05`,
+		`1234567.5`:                `1.2345675e+06`,
+		`1e2`:                      `100`,
+		`12.5e1`:                   `125`,
+		`1e400`:                    `1e+400`,
+		`1e20`:                     `100000000000000000000`,
+		`1.5e300`:                  `1.5e+300`,
+		`1e1001`:                   `1e+1001`,
+		`0.0e5`:                    `0`,
+		`123456789012345678901e-2`: `1.23456789012345678901e+18`,
+	}
+	for number, expected := range decimals {
+		kv, err := NewFromString(`{"p":` + number + `}`)
+		suite.Require().NoError(err)
+		canonical, err := kv.Canonical()
+		suite.Require().NoError(err)
+		suite.Require().Equal(`{"p":`+expected+`}`, string(canonical), number)
+	}
+
+	// the nil values are not allowed even in the lists
+	_, err = KeyValue{"list": []interface{}{nil}}.Canonical()
+	suite.Require().Error(err)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestKeyValue(t *testing.T) {
@@ -1,15 +1,25 @@
 package key_value
 
 import (
+	"container/list"
 	"fmt"
 	"reflect"
 
 	"github.com/ahmetson/datatype-lib/data_type"
 )
 
+// listEntry is the element of the List kept in the order of addition
+type listEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// List is the map that remembers the order of the addition.
+// The map gives access to the element by the key,
+// while the linked list keeps the order.
 type List struct {
-	l         map[interface{}]interface{}
-	length    uint
+	l         map[interface{}]*list.Element
+	order     *list.List
 	cap       uint
 	keyType   reflect.Type
 	valueType reflect.Type
@@ -30,32 +40,39 @@ func NewList() *List {
 		keyType:   nil,
 		valueType: nil,
 		cap:       DefaultCap,
-		length:    0,
-		l:         map[interface{}]interface{}{},
+		l:         map[interface{}]*list.Element{},
+		order:     list.New(),
 	}
 }
 
 func (q *List) Len() uint {
-	return q.length
+	return uint(len(q.l))
 }
 
 func (q *List) IsEmpty() bool {
-	return q.length == 0
+	return len(q.l) == 0
 }
 
 func (q *List) IsFull() bool {
-	return q.length == q.cap
+	return q.Len() == q.cap
 }
 
+// List returns the elements as a map.
+// The returned map is a copy, changing it doesn't affect the list.
 func (q *List) List() map[interface{}]interface{} {
-	return q.l
+	elements := make(map[interface{}]interface{}, len(q.l))
+	for key, element := range q.l {
+		elements[key] = element.Value.(*listEntry).value
+	}
+
+	return elements
 }
 
 // SetCap updates the capacity of the list.
 // If the list has more elements than newCap, it throws an error.
 func (q *List) SetCap(newCap uint) error {
-	if q.length > newCap {
-		return fmt.Errorf("list has %d elements. can not set cap to %d", q.length, newCap)
+	if q.Len() > newCap {
+		return fmt.Errorf("list has %d elements. can not set cap to %d", q.Len(), newCap)
 	}
 
 	q.cap = newCap
@@ -77,6 +94,25 @@ func (q *List) Add(key interface{}, value interface{}) error {
 	if q.IsFull() {
 		return fmt.Errorf("list is already full")
 	}
+	if err := q.validate(key, value); err != nil {
+		return err
+	}
+	if _, ok := q.l[key]; ok {
+		return fmt.Errorf("the element exists")
+	}
+
+	if q.keyType == nil {
+		q.keyType = reflect.TypeOf(key)
+		q.valueType = reflect.TypeOf(value)
+	}
+
+	q.l[key] = q.order.PushBack(&listEntry{key: key, value: value})
+	return nil
+}
+
+// validate checks that the key and value could be added into the list.
+// The key and value types must be the same as the types of the first added element.
+func (q *List) validate(key interface{}, value interface{}) error {
 	if data_type.IsNil(key) {
 		return fmt.Errorf("the kv parameter is nil")
 	}
@@ -86,25 +122,18 @@ func (q *List) Add(key interface{}, value interface{}) error {
 	if data_type.IsNil(value) {
 		return fmt.Errorf("the value parameer is nil")
 	}
-
-	keyType := reflect.TypeOf(key)
-	valueType := reflect.TypeOf(value)
-
 	if q.keyType == nil {
-		q.keyType = keyType
-		q.valueType = valueType
-	} else if _, ok := q.l[key]; ok {
-		return fmt.Errorf("the element exists")
+		return nil
 	}
 
+	keyType := reflect.TypeOf(key)
+	valueType := reflect.TypeOf(value)
 	if keyType == q.keyType && valueType == q.valueType {
-		q.l[key] = value
-		q.length++
 		return nil
 	}
 
 	return fmt.Errorf(
-		"expected kv type %T against %T and expected value type %T against %T",
+		"expected kv type %v against %v and expected value type %v against %v",
 		q.keyType,
 		keyType,
 		q.valueType,
@@ -150,21 +179,57 @@ func (q *List) Get(key interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("the data mismatch: expected kv type %T against %T", q.keyType, keyType)
 	}
 
-	value, ok := q.l[key]
+	element, ok := q.l[key]
 	if !ok {
 		return nil, fmt.Errorf("the element not found")
 	}
-	return value, nil
+	return element.Value.(*listEntry).value, nil
 }
 
 // GetFirst returns the first added element.
 // Returns the kv, value and error if it can not find it.
 func (q *List) GetFirst() (interface{}, interface{}, error) {
-	for key, value := range q.l {
-		return key, value, nil
+	element := q.order.Front()
+	if element == nil {
+		return nil, nil, fmt.Errorf("empty list")
+	}
+
+	entry := element.Value.(*listEntry)
+	return entry.key, entry.value, nil
+}
+
+// GetLast returns the last added element.
+// Returns the kv, value and error if it can not find it.
+func (q *List) GetLast() (interface{}, interface{}, error) {
+	element := q.order.Back()
+	if element == nil {
+		return nil, nil, fmt.Errorf("empty list")
+	}
+
+	entry := element.Value.(*listEntry)
+	return entry.key, entry.value, nil
+}
+
+// remove the element by the key from the map and the order
+func (q *List) remove(key interface{}) {
+	element, ok := q.l[key]
+	if !ok {
+		return
 	}
 
-	return nil, nil, fmt.Errorf("empty list")
+	q.order.Remove(element)
+	delete(q.l, key)
+}
+
+// replace the value of the existing element keeping its position.
+// The value must be validated by the caller.
+func (q *List) replace(key interface{}, value interface{}) {
+	element, ok := q.l[key]
+	if !ok {
+		return
+	}
+
+	element.Value.(*listEntry).value = value
 }
 
 // Take is a Get, but removes the returned element from the list
@@ -174,8 +239,7 @@ func (q *List) Take(key interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("failed to get the element")
 	}
 
-	delete(q.l, key)
-	q.length--
+	q.remove(key)
 
 	return value, nil
 }
@@ -187,8 +251,51 @@ func (q *List) TakeFirst() (interface{}, interface{}, error) {
 		return nil, nil, fmt.Errorf("list.GetFirst: %w", err)
 	}
 
-	delete(q.l, key)
-	q.length--
+	q.remove(key)
 
 	return key, value, nil
 }
+
+// MoveToBack makes the element the last one as if it was added just now.
+// Use it to track the least recently used elements at the front of the list.
+func (q *List) MoveToBack(key interface{}) error {
+	if !q.Exist(key) {
+		return fmt.Errorf("the element not found")
+	}
+
+	q.order.MoveToBack(q.l[key])
+
+	return nil
+}
+
+// Keys returns the keys in the order of addition
+func (q *List) Keys() []interface{} {
+	keys := make([]interface{}, 0, len(q.l))
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*listEntry).key)
+	}
+
+	return keys
+}
+
+// Values returns the values in the order of addition
+func (q *List) Values() []interface{} {
+	values := make([]interface{}, 0, len(q.l))
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		values = append(values, element.Value.(*listEntry).value)
+	}
+
+	return values
+}
+
+// Range calls f for each element in the order of addition.
+// If f returns false, then the iteration stops.
+// The list must not be changed during the iteration.
+func (q *List) Range(f func(key interface{}, value interface{}) bool) {
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*listEntry)
+		if !f(entry.key, entry.value) {
+			return
+		}
+	}
+}
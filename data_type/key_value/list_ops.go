@@ -0,0 +1,152 @@
+package key_value
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// emptyCopy returns the empty list with the same cap and element types
+func (q *List) emptyCopy() *List {
+	copied := NewList()
+	copied.cap = q.cap
+	copied.keyType = q.keyType
+	copied.valueType = q.valueType
+
+	return copied
+}
+
+// AddMany adds the elements where keys[i] is the key of values[i].
+// Either all elements are added, or none of them.
+// It fails if the list has no space for all elements,
+// if any element breaks the List rules, or the key exists.
+func (q *List) AddMany(keys []interface{}, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("%d keys against %d values", len(keys), len(values))
+	}
+	if q.Len()+uint(len(keys)) > q.cap {
+		return fmt.Errorf("list has %d elements, can not add %d more with cap %d", q.Len(), len(keys), q.cap)
+	}
+
+	// the batch is validated against the copy of the types,
+	// so the empty list is not changed if the batch fails
+	checker := q.emptyCopy()
+	added := make(map[interface{}]struct{}, len(keys))
+	for i, key := range keys {
+		if err := checker.validate(key, values[i]); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		if checker.keyType == nil {
+			checker.keyType = reflect.TypeOf(key)
+			checker.valueType = reflect.TypeOf(values[i])
+		}
+		if _, ok := q.l[key]; ok {
+			return fmt.Errorf("element %d: the element exists", i)
+		}
+		if _, ok := added[key]; ok {
+			return fmt.Errorf("element %d: the key is duplicated", i)
+		}
+		added[key] = struct{}{}
+	}
+
+	for i, key := range keys {
+		// validated above, therefore, it can't fail
+		_ = q.Add(key, values[i])
+	}
+
+	return nil
+}
+
+// Filter returns a new list with the elements for which f returns true.
+// The order and the cap are kept.
+func (q *List) Filter(f func(key interface{}, value interface{}) bool) *List {
+	filtered := q.emptyCopy()
+	q.Range(func(key interface{}, value interface{}) bool {
+		if f(key, value) {
+			_ = filtered.Add(key, value)
+		}
+		return true
+	})
+
+	return filtered
+}
+
+// Map returns a new list where the values are replaced by the values returned by f.
+// The new values must follow the List rules.
+// The order and the cap are kept.
+func (q *List) Map(f func(key interface{}, value interface{}) (interface{}, error)) (*List, error) {
+	mapped := NewList()
+	mapped.cap = q.cap
+
+	var err error
+	q.Range(func(key interface{}, value interface{}) bool {
+		var newValue interface{}
+		newValue, err = f(key, value)
+		if err != nil {
+			err = fmt.Errorf("f(%v): %w", key, err)
+			return false
+		}
+		if err = mapped.Add(key, newValue); err != nil {
+			err = fmt.Errorf("mapped.Add(%v): %w", key, err)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapped, nil
+}
+
+// Merge returns a new list with the elements of this list,
+// followed by the elements of the other list which keys are not in this list.
+// For the duplicate keys, the value of this list is kept.
+// The cap of this list is kept.
+func (q *List) Merge(other *List) (*List, error) {
+	merged := q.Filter(func(interface{}, interface{}) bool { return true })
+
+	var err error
+	other.Range(func(key interface{}, value interface{}) bool {
+		if merged.Exist(key) {
+			return true
+		}
+		if err = merged.Add(key, value); err != nil {
+			err = fmt.Errorf("merged.Add(%v): %w", key, err)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// Intersect returns a new list with the elements of this list
+// which keys are in the other list.
+func (q *List) Intersect(other *List) *List {
+	return q.Filter(func(key interface{}, _ interface{}) bool {
+		return other.Exist(key)
+	})
+}
+
+// Difference returns a new list with the elements of this list
+// which keys are not in the other list.
+func (q *List) Difference(other *List) *List {
+	return q.Filter(func(key interface{}, _ interface{}) bool {
+		return !other.Exist(key)
+	})
+}
+
+// TakeWhere removes the elements for which the predicate returns true.
+// The removed elements are returned as a new list.
+func (q *List) TakeWhere(predicate func(key interface{}, value interface{}) bool) *List {
+	taken := q.Filter(predicate)
+	taken.Range(func(key interface{}, _ interface{}) bool {
+		q.remove(key)
+		return true
+	})
+
+	return taken
+}
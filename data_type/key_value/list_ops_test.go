@@ -0,0 +1,130 @@
+package key_value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestListOpsSuite struct {
+	suite.Suite
+	list *List
+}
+
+// SetupTest prepares the list of four elements: a=1, b=2, c=3, d=4
+func (suite *TestListOpsSuite) SetupTest() {
+	list := NewList()
+	err := list.AddMany(
+		[]interface{}{"a", "b", "c", "d"},
+		[]interface{}{uint64(1), uint64(2), uint64(3), uint64(4)},
+	)
+	suite.Require().NoError(err)
+	suite.Require().EqualValues(4, list.Len())
+
+	suite.list = list
+}
+
+func (suite *TestListOpsSuite) TestAddMany() {
+	// the number of keys and values are not matching
+	err := suite.list.AddMany([]interface{}{"e"}, []interface{}{})
+	suite.Require().Error(err)
+
+	// one of the elements exists
+	err = suite.list.AddMany([]interface{}{"e", "a"}, []interface{}{uint64(5), uint64(6)})
+	suite.Require().Error(err)
+	suite.Require().False(suite.list.Exist("e"))
+
+	// one of the elements has another type
+	err = suite.list.AddMany([]interface{}{"e", "f"}, []interface{}{uint64(5), 6})
+	suite.Require().Error(err)
+	suite.Require().False(suite.list.Exist("e"))
+
+	// duplicate key in the batch
+	err = suite.list.AddMany([]interface{}{"e", "e"}, []interface{}{uint64(5), uint64(6)})
+	suite.Require().Error(err)
+
+	// no space for all of them
+	suite.Require().NoError(suite.list.SetCap(5))
+	err = suite.list.AddMany([]interface{}{"e", "f"}, []interface{}{uint64(5), uint64(6)})
+	suite.Require().Error(err)
+	suite.Require().EqualValues(4, suite.list.Len())
+
+	// the batch in the empty list must have the same types
+	empty := NewList()
+	err = empty.AddMany([]interface{}{"a", "b"}, []interface{}{uint64(1), "2"})
+	suite.Require().Error(err)
+	suite.Require().True(empty.IsEmpty())
+	suite.Require().NoError(empty.Add(1, "1"))
+}
+
+func (suite *TestListOpsSuite) TestFilterMap() {
+	even := suite.list.Filter(func(_ interface{}, value interface{}) bool {
+		return value.(uint64)%2 == 0
+	})
+	suite.Require().Equal([]interface{}{"b", "d"}, even.Keys())
+	suite.Require().Equal(suite.list.Cap(), even.Cap())
+	suite.Require().EqualValues(4, suite.list.Len())
+
+	named, err := suite.list.Map(func(key interface{}, value interface{}) (interface{}, error) {
+		return fmt.Sprintf("%s=%d", key, value), nil
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal([]interface{}{"a=1", "b=2", "c=3", "d=4"}, named.Values())
+
+	// the new values must have the same type
+	_, err = suite.list.Map(func(key interface{}, value interface{}) (interface{}, error) {
+		if key == "c" {
+			return "c", nil
+		}
+		return value, nil
+	})
+	suite.Require().Error(err)
+}
+
+func (suite *TestListOpsSuite) TestSetOperations() {
+	other := NewList()
+	suite.Require().NoError(other.AddMany(
+		[]interface{}{"c", "e", "a"},
+		[]interface{}{uint64(30), uint64(50), uint64(10)},
+	))
+
+	merged, err := suite.list.Merge(other)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]interface{}{"a", "b", "c", "d", "e"}, merged.Keys())
+	suite.Require().Equal([]interface{}{uint64(1), uint64(2), uint64(3), uint64(4), uint64(50)}, merged.Values())
+
+	intersected := suite.list.Intersect(other)
+	suite.Require().Equal([]interface{}{"a", "c"}, intersected.Keys())
+	suite.Require().Equal([]interface{}{uint64(1), uint64(3)}, intersected.Values())
+
+	difference := suite.list.Difference(other)
+	suite.Require().Equal([]interface{}{"b", "d"}, difference.Keys())
+
+	// merging the list of other types
+	invalid := NewList()
+	suite.Require().NoError(invalid.Add(1, 1))
+	_, err = suite.list.Merge(invalid)
+	suite.Require().Error(err)
+}
+
+func (suite *TestListOpsSuite) TestTakeWhere() {
+	taken := suite.list.TakeWhere(func(_ interface{}, value interface{}) bool {
+		return value.(uint64) > 2
+	})
+	suite.Require().Equal([]interface{}{"c", "d"}, taken.Keys())
+	suite.Require().Equal([]interface{}{"a", "b"}, suite.list.Keys())
+
+	none := suite.list.TakeWhere(func(interface{}, interface{}) bool { return false })
+	suite.Require().True(none.IsEmpty())
+	suite.Require().EqualValues(2, suite.list.Len())
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestListOps(t *testing.T) {
+	suite.Run(t, new(TestListOpsSuite))
+}
@@ -109,6 +109,60 @@ func (suite *TestListQueue) TestListLimit() {
 	suite.Require().Error(err)
 }
 
+func (suite *TestListQueue) TestOrder() {
+	_, _, err := suite.list.GetFirst()
+	suite.Require().Error(err)
+	_, _, err = suite.list.GetLast()
+	suite.Require().Error(err)
+
+	// the order of the keys differs from the hash order
+	keys := []string{"zeta", "alpha", "mu", "beta", "omega", "gamma"}
+	for i, key := range keys {
+		suite.Require().NoError(suite.list.Add(key, i))
+	}
+	suite.Require().Equal([]interface{}{"zeta", "alpha", "mu", "beta", "omega", "gamma"}, suite.list.Keys())
+	suite.Require().Equal([]interface{}{0, 1, 2, 3, 4, 5}, suite.list.Values())
+
+	key, value, err := suite.list.GetFirst()
+	suite.Require().NoError(err)
+	suite.Require().Equal("zeta", key)
+	suite.Require().Equal(0, value)
+
+	key, value, err = suite.list.GetLast()
+	suite.Require().NoError(err)
+	suite.Require().Equal("gamma", key)
+	suite.Require().Equal(5, value)
+
+	// the least recently used goes to the back
+	suite.Require().NoError(suite.list.MoveToBack("zeta"))
+	suite.Require().Error(suite.list.MoveToBack("not_exist"))
+	key, _, err = suite.list.GetLast()
+	suite.Require().NoError(err)
+	suite.Require().Equal("zeta", key)
+
+	_, err = suite.list.Take("mu")
+	suite.Require().NoError(err)
+
+	key, value, err = suite.list.TakeFirst()
+	suite.Require().NoError(err)
+	suite.Require().Equal("alpha", key)
+	suite.Require().Equal(1, value)
+	suite.Require().EqualValues(4, suite.list.Len())
+
+	// range stops when false is returned
+	visited := make([]interface{}, 0)
+	suite.list.Range(func(key interface{}, _ interface{}) bool {
+		visited = append(visited, key)
+		return len(visited) < 3
+	})
+	suite.Require().Equal([]interface{}{"beta", "omega", "gamma"}, visited)
+
+	// the returned map doesn't change the list
+	elements := suite.list.List()
+	delete(elements, "beta")
+	suite.Require().True(suite.list.Exist("beta"))
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestList(t *testing.T) {
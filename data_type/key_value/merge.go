@@ -0,0 +1,158 @@
+package key_value
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MergeStrategy defines how DeepMerge resolves the keys that exist in both KeyValues.
+// The nested KeyValues are always merged key by key.
+type MergeStrategy uint8
+
+const (
+	// MergeOverride replaces the existing value with the other value
+	MergeOverride MergeStrategy = iota
+	// MergeKeepExisting keeps the existing value
+	MergeKeepExisting
+	// MergeConcatLists appends the other list to the existing list.
+	// The other values replace the existing values.
+	MergeConcatLists
+)
+
+// DeepMerge merges the other KeyValue into k.
+// The nested KeyValues are merged recursively,
+// the rest of the values are resolved by the strategy.
+// The values of other are copied, so changing other later doesn't change k.
+//
+// For example, to layer the default configuration under the user parameters:
+//
+//	parameters.DeepMerge(defaults, key_value.MergeKeepExisting)
+func (k KeyValue) DeepMerge(other KeyValue, strategy MergeStrategy) error {
+	if strategy > MergeConcatLists {
+		return fmt.Errorf("unsupported merge strategy %d", strategy)
+	}
+	if err := noNil(other); err != nil {
+		return fmt.Errorf("noNil: %w", err)
+	}
+
+	deepMerge(k, other, strategy)
+
+	return nil
+}
+
+// deepMerge merges the other map into the target map
+func deepMerge(target KeyValue, other KeyValue, strategy MergeStrategy) {
+	for key, otherValue := range other {
+		value, ok := target[key]
+		if !ok {
+			target[key] = deepCopy(otherValue)
+			continue
+		}
+
+		nested, isMap := toKeyValue(value)
+		otherNested, otherIsMap := toKeyValue(otherValue)
+		if isMap && otherIsMap {
+			deepMerge(nested, otherNested, strategy)
+			continue
+		}
+
+		switch strategy {
+		case MergeKeepExisting:
+			continue
+		case MergeConcatLists:
+			if concatenated, ok := concatLists(value, otherValue); ok {
+				target[key] = concatenated
+				continue
+			}
+		}
+
+		target[key] = deepCopy(otherValue)
+	}
+}
+
+// concatLists returns the list with the elements of a followed by the elements of b.
+// The string lists stay string lists, other lists become []interface{}.
+// Returns false if any of the values is not a list.
+func concatLists(a interface{}, b interface{}) (interface{}, bool) {
+	aStrings, aOk := a.([]string)
+	bStrings, bOk := b.([]string)
+	if aOk && bOk {
+		concatenated := make([]string, 0, len(aStrings)+len(bStrings))
+		concatenated = append(concatenated, aStrings...)
+		return append(concatenated, bStrings...), true
+	}
+
+	aList, aOk := toList(a)
+	bList, bOk := toList(b)
+	if !aOk || !bOk {
+		return nil, false
+	}
+
+	concatenated := make([]interface{}, 0, len(aList)+len(bList))
+	for _, element := range aList {
+		concatenated = append(concatenated, deepCopy(element))
+	}
+	for _, element := range bList {
+		concatenated = append(concatenated, deepCopy(element))
+	}
+
+	return concatenated, true
+}
+
+// MergePatch applies the JSON Merge Patch (RFC 7396).
+// The patch is a json object, where the null value removes the key,
+// the objects are merged recursively, and the rest of the values replace the existing ones.
+//
+// The null is allowed only as the patch instruction.
+// Either the patch is applied entirely, or k is not changed.
+func (k KeyValue) MergePatch(patch []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.UseNumber()
+
+	var patchKv map[string]interface{}
+	if err := decoder.Decode(&patchKv); err != nil {
+		return fmt.Errorf("json.decoder: '%w'", err)
+	}
+	if patchKv == nil {
+		return fmt.Errorf("merge patch must be a json object")
+	}
+
+	patched := k.deepCopy()
+	mergePatch(patched, patchKv)
+	if err := noNil(patched); err != nil {
+		return fmt.Errorf("noNil: %w", err)
+	}
+
+	for key := range k {
+		delete(k, key)
+	}
+	for key, value := range patched {
+		k[key] = value
+	}
+
+	return nil
+}
+
+// mergePatch applies the patch object to the target object
+func mergePatch(target KeyValue, patch KeyValue) {
+	for key, patchValue := range patch {
+		if patchValue == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchNested, ok := toKeyValue(patchValue)
+		if !ok {
+			target[key] = patchValue
+			continue
+		}
+
+		nested, ok := toKeyValue(target[key])
+		if !ok {
+			nested = New()
+			target[key] = nested
+		}
+		mergePatch(nested, patchNested)
+	}
+}
@@ -0,0 +1,106 @@
+package key_value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestMergeSuite struct {
+	suite.Suite
+	defaults KeyValue
+	user     KeyValue
+}
+
+// SetupTest prepares the default and user parameters
+func (suite *TestMergeSuite) SetupTest() {
+	defaults, err := NewFromString(`{"timeout":10,"retries":3,"hosts":["a"],"db":{"name":"sds","port":5432}}`)
+	suite.Require().NoError(err)
+	user, err := NewFromString(`{"timeout":30,"hosts":["b","c"],"db":{"port":6543,"user":"admin"}}`)
+	suite.Require().NoError(err)
+
+	suite.defaults = defaults
+	suite.user = user
+}
+
+func (suite *TestMergeSuite) TestOverride() {
+	suite.Require().NoError(suite.defaults.DeepMerge(suite.user, MergeOverride))
+
+	suite.Require().Equal(
+		`{"db":{"name":"sds","port":6543,"user":"admin"},"hosts":["b","c"],"retries":3,"timeout":30}`,
+		suite.defaults.String(),
+	)
+
+	// the merged values are copied
+	suite.Require().NoError(suite.user.SetAt("db.user", "root"))
+	user, err := suite.defaults.StringAt("db.user")
+	suite.Require().NoError(err)
+	suite.Require().Equal("admin", user)
+}
+
+func (suite *TestMergeSuite) TestKeepExisting() {
+	suite.Require().NoError(suite.user.DeepMerge(suite.defaults, MergeKeepExisting))
+
+	suite.Require().Equal(
+		`{"db":{"name":"sds","port":6543,"user":"admin"},"hosts":["b","c"],"retries":3,"timeout":30}`,
+		suite.user.String(),
+	)
+}
+
+func (suite *TestMergeSuite) TestConcatLists() {
+	suite.Require().NoError(suite.defaults.DeepMerge(suite.user, MergeConcatLists))
+
+	hosts, err := suite.defaults.StringsValue("hosts")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a", "b", "c"}, hosts)
+
+	timeout, err := suite.defaults.Uint64Value("timeout")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(30), timeout)
+
+	strings := KeyValue{"list": []string{"a"}}
+	suite.Require().NoError(strings.DeepMerge(KeyValue{"list": []string{"b"}}, MergeConcatLists))
+	suite.Require().Equal([]string{"a", "b"}, strings["list"])
+}
+
+func (suite *TestMergeSuite) TestInvalidMerge() {
+	suite.Require().Error(suite.defaults.DeepMerge(KeyValue{"nil": nil}, MergeOverride))
+	suite.Require().Error(suite.defaults.DeepMerge(suite.user, MergeStrategy(100)))
+}
+
+func (suite *TestMergeSuite) TestMergePatch() {
+	// the example from RFC 7396
+	target, err := NewFromString(`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`)
+	suite.Require().NoError(err)
+
+	patch := `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`
+	suite.Require().NoError(target.MergePatch([]byte(patch)))
+
+	suite.Require().Equal(
+		`{"author":{"givenName":"John"},"content":"This will be unchanged","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`,
+		target.String(),
+	)
+
+	// the nested objects are created, nulls are not kept
+	suite.Require().NoError(target.MergePatch([]byte(`{"meta":{"a":{"b":1,"c":null}}}`)))
+	nested, err := target.NestedAt("meta.a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{"b":1}`, nested.String())
+
+	// invalid patches don't change the target
+	original := target.String()
+	suite.Require().Error(target.MergePatch([]byte(`["not an object"]`)))
+	suite.Require().Error(target.MergePatch([]byte(`null`)))
+	suite.Require().Error(target.MergePatch([]byte(`{"title":`)))
+	suite.Require().Error(target.MergePatch([]byte(`{"title":"new","tags":[null]}`)))
+	suite.Require().Equal(original, target.String())
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestMerge(t *testing.T) {
+	suite.Run(t, new(TestMergeSuite))
+}
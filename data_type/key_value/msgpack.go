@@ -0,0 +1,331 @@
+package key_value
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// MessagePackBigIntExt is the MessagePack extension type of *big.Int.
+// The payload is the sign byte (0 for positive, 1 for negative) followed by the big-endian absolute value.
+const MessagePackBigIntExt int8 = 1
+
+// MessagePack serializes k into the MessagePack format.
+//
+// The unsigned integers are written in the unsigned formats, the signed integers in the signed formats,
+// so uint64, int64 and float64 are kept after NewFromMessagePack.
+// The *big.Int and the numbers that don't fit into 64 bits are written as MessagePackBigIntExt.
+// The []byte is written as the binary, not as base64 string.
+// The keys are sorted, so the same KeyValue gives the same bytes.
+func (k KeyValue) MessagePack() ([]byte, error) {
+	value, err := binaryMap(k)
+	if err != nil {
+		return nil, fmt.Errorf("binaryMap: %w", err)
+	}
+
+	return appendMessagePack(make([]byte, 0, 64), value), nil
+}
+
+// NewFromMessagePack decodes the MessagePack map into KeyValue.
+// The nil values are not allowed, same as in NewFromString.
+func NewFromMessagePack(data []byte) (KeyValue, error) {
+	r := &binaryReader{data: data}
+	value, err := readMessagePack(r)
+	if err != nil {
+		return nil, fmt.Errorf("readMessagePack: %w", err)
+	}
+
+	kv, err := binaryToKeyValue(value, r)
+	if err != nil {
+		return nil, fmt.Errorf("binaryToKeyValue: %w", err)
+	}
+
+	return kv, nil
+}
+
+// appendMessagePack appends the value converted by binaryValue
+func appendMessagePack(buffer []byte, value interface{}) []byte {
+	switch typed := value.(type) {
+	case bool:
+		if typed {
+			return append(buffer, 0xc3)
+		}
+		return append(buffer, 0xc2)
+	case uint64:
+		switch {
+		case typed < 0x80:
+			return append(buffer, byte(typed))
+		case typed <= math.MaxUint8:
+			return putUint(append(buffer, 0xcc), typed, 1)
+		case typed <= math.MaxUint16:
+			return putUint(append(buffer, 0xcd), typed, 2)
+		case typed <= math.MaxUint32:
+			return putUint(append(buffer, 0xce), typed, 4)
+		}
+		return putUint(append(buffer, 0xcf), typed, 8)
+	case int64:
+		// the positive fixint is not used, so the value is decoded as a signed one
+		switch {
+		case typed >= -32 && typed < 0:
+			return append(buffer, byte(typed))
+		case typed >= math.MinInt8 && typed <= math.MaxInt8:
+			return putUint(append(buffer, 0xd0), uint64(typed), 1)
+		case typed >= math.MinInt16 && typed <= math.MaxInt16:
+			return putUint(append(buffer, 0xd1), uint64(typed), 2)
+		case typed >= math.MinInt32 && typed <= math.MaxInt32:
+			return putUint(append(buffer, 0xd2), uint64(typed), 4)
+		}
+		return putUint(append(buffer, 0xd3), uint64(typed), 8)
+	case float64:
+		return putUint(append(buffer, 0xcb), math.Float64bits(typed), 8)
+	case string:
+		length := uint64(len(typed))
+		switch {
+		case length < 32:
+			buffer = append(buffer, 0xa0|byte(length))
+		case length <= math.MaxUint8:
+			buffer = putUint(append(buffer, 0xd9), length, 1)
+		case length <= math.MaxUint16:
+			buffer = putUint(append(buffer, 0xda), length, 2)
+		default:
+			buffer = putUint(append(buffer, 0xdb), length, 4)
+		}
+		return append(buffer, typed...)
+	case []byte:
+		buffer = appendMessagePackLength(buffer, uint64(len(typed)), 0xc4, 0xc5, 0xc6)
+		return append(buffer, typed...)
+	case *big.Int:
+		payload := append([]byte{0}, typed.Bytes()...)
+		if typed.Sign() < 0 {
+			payload[0] = 1
+		}
+		buffer = appendMessagePackLength(buffer, uint64(len(payload)), 0xc7, 0xc8, 0xc9)
+		buffer = append(buffer, byte(MessagePackBigIntExt))
+		return append(buffer, payload...)
+	case []interface{}:
+		length := uint64(len(typed))
+		switch {
+		case length < 16:
+			buffer = append(buffer, 0x90|byte(length))
+		case length <= math.MaxUint16:
+			buffer = putUint(append(buffer, 0xdc), length, 2)
+		default:
+			buffer = putUint(append(buffer, 0xdd), length, 4)
+		}
+		for _, element := range typed {
+			buffer = appendMessagePack(buffer, element)
+		}
+		return buffer
+	case map[string]interface{}:
+		length := uint64(len(typed))
+		switch {
+		case length < 16:
+			buffer = append(buffer, 0x80|byte(length))
+		case length <= math.MaxUint16:
+			buffer = putUint(append(buffer, 0xde), length, 2)
+		default:
+			buffer = putUint(append(buffer, 0xdf), length, 4)
+		}
+
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			buffer = appendMessagePack(buffer, key)
+			buffer = appendMessagePack(buffer, typed[key])
+		}
+		return buffer
+	}
+
+	// binaryValue returns only the types above
+	panic(fmt.Sprintf("unsupported type %T", value))
+}
+
+// appendMessagePackLength appends the header of 8, 16 or 32 bits length
+func appendMessagePackLength(buffer []byte, length uint64, format8 byte, format16 byte, format32 byte) []byte {
+	switch {
+	case length <= math.MaxUint8:
+		return putUint(append(buffer, format8), length, 1)
+	case length <= math.MaxUint16:
+		return putUint(append(buffer, format16), length, 2)
+	}
+	return putUint(append(buffer, format32), length, 4)
+}
+
+// readMessagePack reads one value
+func readMessagePack(r *binaryReader) (interface{}, error) {
+	format, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case format <= 0x7f:
+		return uint64(format), nil
+	case format >= 0xe0:
+		return int64(int8(format)), nil
+	case format >= 0x80 && format <= 0x8f:
+		return readMessagePackMap(r, uint64(format&0x0f))
+	case format >= 0x90 && format <= 0x9f:
+		return readMessagePackArray(r, uint64(format&0x0f))
+	case format >= 0xa0 && format <= 0xbf:
+		return readMessagePackString(r, uint64(format&0x1f))
+	}
+
+	switch format {
+	case 0xc0:
+		return nil, fmt.Errorf("value at %d is %w", r.offset-1, ErrNilValue)
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		length, err := r.readUint(1 << (format - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.readBytes(length)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, b...), nil
+	case 0xc7, 0xc8, 0xc9:
+		length, err := r.readUint(1 << (format - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackExt(r, length)
+	case 0xca:
+		bits, err := r.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(bits))), nil
+	case 0xcb:
+		bits, err := r.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return r.readUint(1 << (format - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (format - 0xd0)
+		bits, err := r.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		// extend the sign of the smaller integers
+		shift := uint(64 - 8*size)
+		return int64(bits<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return readMessagePackExt(r, 1<<(format-0xd4))
+	case 0xd9, 0xda, 0xdb:
+		length, err := r.readUint(1 << (format - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackString(r, length)
+	case 0xdc, 0xdd:
+		length, err := r.readUint(2 << (format - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackArray(r, length)
+	case 0xde, 0xdf:
+		length, err := r.readUint(2 << (format - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackMap(r, length)
+	}
+
+	return nil, fmt.Errorf("unsupported format 0x%x at %d", format, r.offset-1)
+}
+
+func readMessagePackString(r *binaryReader, length uint64) (string, error) {
+	b, err := r.readBytes(length)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func readMessagePackArray(r *binaryReader, length uint64) ([]interface{}, error) {
+	if err := r.checkLength(length); err != nil {
+		return nil, err
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+
+	list := make([]interface{}, length)
+	for i := range list {
+		element, err := readMessagePack(r)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		list[i] = element
+	}
+
+	return list, nil
+}
+
+func readMessagePackMap(r *binaryReader, length uint64) (map[string]interface{}, error) {
+	if err := r.checkLength(length); err != nil {
+		return nil, err
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+
+	decoded := make(map[string]interface{}, length)
+	for i := uint64(0); i < length; i++ {
+		rawKey, err := readMessagePack(r)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		key, ok := rawKey.(string)
+		if !ok {
+			return nil, fmt.Errorf("key %d is %T, not a string", i, rawKey)
+		}
+		value, err := readMessagePack(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		decoded[key] = value
+	}
+
+	return decoded, nil
+}
+
+// readMessagePackExt reads the extension with the payload of the given length.
+// Only MessagePackBigIntExt is supported.
+func readMessagePackExt(r *binaryReader, length uint64) (interface{}, error) {
+	extType, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := r.readBytes(length)
+	if err != nil {
+		return nil, err
+	}
+	if int8(extType) != MessagePackBigIntExt {
+		return nil, fmt.Errorf("unsupported extension type %d", int8(extType))
+	}
+	if len(payload) == 0 || payload[0] > 1 {
+		return nil, fmt.Errorf("invalid big number sign")
+	}
+
+	number := new(big.Int).SetBytes(payload[1:])
+	if payload[0] == 1 {
+		number.Neg(number)
+	}
+
+	return number, nil
+}
@@ -0,0 +1,140 @@
+package key_value
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestMessagePackSuite struct {
+	suite.Suite
+	json string
+}
+
+// SetupTest sets the json form of the parameters
+func (suite *TestMessagePackSuite) SetupTest() {
+	suite.json = `{"big":123456789012345678901234567890,"enabled":false,"fraction":-1.5,` +
+		`"items":[{"qty":1},{"qty":300}],"long":"` + strings.Repeat("x", 40) + `","name":"sds",` +
+		`"negative":-70000,"neg_big":-123456789012345678901234567890,"tags":["a","b"],"uint":18446744073709551615}`
+}
+
+func (suite *TestMessagePackSuite) TestJsonRoundTrip() {
+	kv, err := NewFromString(suite.json)
+	suite.Require().NoError(err)
+
+	encoded, err := kv.MessagePack()
+	suite.Require().NoError(err)
+	decoded, err := NewFromMessagePack(encoded)
+	suite.Require().NoError(err)
+
+	suite.Require().True(kv.Equal(decoded))
+	suite.Require().Equal(kv.String(), decoded.String())
+
+	// the getters work with the decoded values
+	number, err := decoded.Uint64Value("uint")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(math.MaxUint64), number)
+	big, err := decoded.BigIntValue("big")
+	suite.Require().NoError(err)
+	suite.Require().Equal("123456789012345678901234567890", big.String())
+	qty, err := decoded.Uint64At("items.1.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(300), qty)
+	items, err := decoded.NestedListValue("items")
+	suite.Require().NoError(err)
+	suite.Require().Len(items, 2)
+
+	// the same kv gives the same bytes
+	again, err := kv.MessagePack()
+	suite.Require().NoError(err)
+	suite.Require().Equal(encoded, again)
+}
+
+func (suite *TestMessagePackSuite) TestTypes() {
+	supply, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	kv := KeyValue{
+		"uint":     uint64(5),
+		"uint8":    uint8(200),
+		"int":      int64(5),
+		"negative": -1,
+		"int16":    int64(math.MinInt16),
+		"int64":    int64(math.MinInt64),
+		"float":    float64(5),
+		"float32":  float32(0.5),
+		"big":      supply,
+		"small":    big.NewInt(1),
+		"bytes":    []byte{0, 1, 2},
+		"bool":     true,
+		"uints":    []uint64{1, 2},
+		"nested":   KeyValue{"list": []KeyValue{{"a": "b"}}},
+	}
+
+	encoded, err := kv.MessagePack()
+	suite.Require().NoError(err)
+	decoded, err := NewFromMessagePack(encoded)
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(uint64(5), decoded["uint"])
+	suite.Require().Equal(uint64(200), decoded["uint8"])
+	suite.Require().Equal(int64(5), decoded["int"])
+	suite.Require().Equal(int64(-1), decoded["negative"])
+	suite.Require().Equal(int64(math.MinInt16), decoded["int16"])
+	suite.Require().Equal(int64(math.MinInt64), decoded["int64"])
+	suite.Require().Equal(float64(5), decoded["float"])
+	suite.Require().Equal(0.5, decoded["float32"])
+	suite.Require().Equal(supply, decoded["big"])
+	suite.Require().Equal(big.NewInt(1), decoded["small"])
+	suite.Require().Equal([]byte{0, 1, 2}, decoded["bytes"])
+	suite.Require().Equal(true, decoded["bool"])
+	suite.Require().Equal([]interface{}{uint64(1), uint64(2)}, decoded["uints"])
+	suite.Require().Equal(map[string]interface{}{"list": []interface{}{map[string]interface{}{"a": "b"}}}, decoded["nested"])
+	suite.Require().True(kv.Equal(decoded))
+}
+
+func (suite *TestMessagePackSuite) TestFormat() {
+	// {"a":1,"b":[-1,true]}
+	encoded, err := KeyValue{"b": []interface{}{-1, true}, "a": uint64(1)}.MessagePack()
+	suite.Require().NoError(err)
+	suite.Require().Equal("82a16101a16292ffc3", hex.EncodeToString(encoded))
+
+	// the formats written by the other libraries
+	data, _ := hex.DecodeString("83a175cd0100a169d0fba166ca3fc00000")
+	decoded, err := NewFromMessagePack(data)
+	suite.Require().NoError(err)
+	suite.Require().Equal(KeyValue{"u": uint64(256), "i": int64(-5), "f": 1.5}, decoded)
+}
+
+func (suite *TestMessagePackSuite) TestInvalid() {
+	_, err := KeyValue{"nil": nil}.MessagePack()
+	suite.Require().ErrorIs(err, ErrNilValue)
+
+	for _, data := range []string{
+		"",                   // empty
+		"81a161c0",           // nil value
+		"81a161",             // no value
+		"91a161",             // not a map
+		"8101a161",           // not a string key
+		"81a16101ff",         // bytes after the value
+		"81a161dbffffffff61", // too long string
+		"81a161ddffffffff",   // too long array
+		"81a161c1",           // never used format
+		"81a161d40501",       // unknown extension
+	} {
+		raw, _ := hex.DecodeString(data)
+		_, err := NewFromMessagePack(raw)
+		suite.Require().Error(err, data)
+	}
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestMessagePack(t *testing.T) {
+	suite.Run(t, new(TestMessagePackSuite))
+}
@@ -0,0 +1,241 @@
+package key_value
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The JSON Pointer (RFC 6901) refers to the value: "/order/items/0/qty".
+// The "~" and "/" in the keys are escaped as "~0" and "~1".
+// The empty pointer refers to the whole KeyValue.
+
+// The operations of the JSON Patch (RFC 6902)
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// PatchOp is the operation of the JSON Patch (RFC 6902).
+// The From is used by the move and copy operations.
+// The Value is used by the add, replace and test operations.
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// parsePointer returns the unescaped segments of the JSON Pointer.
+// The empty pointer has no segments.
+func parsePointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("pointer '%s' must start with '/'", pointer)
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		// after removing the escaped sequences, no '~' must remain
+		if strings.Contains(strings.NewReplacer("~0", "", "~1", "").Replace(segment), "~") {
+			return nil, fmt.Errorf("pointer '%s' has invalid escape sequence in '%s'", pointer, segment)
+		}
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+
+	return segments, nil
+}
+
+// escapePointer escapes the key to be used as a segment of the JSON Pointer
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// Pointer returns the value referred by the JSON Pointer (RFC 6901).
+// The empty pointer returns k itself.
+func (k KeyValue) Pointer(pointer string) (interface{}, error) {
+	segments, err := parsePointer(pointer)
+	if err != nil {
+		return nil, fmt.Errorf("parsePointer: %w", err)
+	}
+
+	value, err := valueAt(k, segments)
+	if err != nil {
+		return nil, fmt.Errorf("valueAt: %w", err)
+	}
+
+	return value, nil
+}
+
+// ApplyPatch applies the JSON Patch (RFC 6902) operations in the given order.
+// Either all operations are applied, or k is not changed.
+//
+// Same as Set, the operations can not add nil values.
+func (k KeyValue) ApplyPatch(ops []PatchOp) error {
+	var patched interface{} = k.deepCopy()
+
+	for i, op := range ops {
+		var err error
+		patched, err = applyPatchOp(patched, op)
+		if err != nil {
+			return fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	result, ok := toKeyValue(patched)
+	if !ok {
+		return fmt.Errorf("patched value is %T, not a kv-value", patched)
+	}
+	for key := range k {
+		delete(k, key)
+	}
+	for key, value := range result {
+		k[key] = value
+	}
+
+	return nil
+}
+
+// applyPatchOp applies the operation to the document and returns the changed document
+func applyPatchOp(document interface{}, op PatchOp) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, fmt.Errorf("parsePointer(path): %w", err)
+	}
+
+	switch op.Op {
+	case PatchAdd:
+		if err := noNil(op.Value); err != nil {
+			return nil, fmt.Errorf("noNil: %w", err)
+		}
+		return addAt(document, path, deepCopy(op.Value))
+	case PatchRemove:
+		if len(path) == 0 {
+			return nil, fmt.Errorf("can not remove the whole document")
+		}
+		return modifyAt(document, path, false, deleteChild)
+	case PatchReplace:
+		if err := noNil(op.Value); err != nil {
+			return nil, fmt.Errorf("noNil: %w", err)
+		}
+		if len(path) == 0 {
+			return deepCopy(op.Value), nil
+		}
+		value := deepCopy(op.Value)
+		return modifyAt(document, path, false, func(parent interface{}, segment string) (interface{}, error) {
+			if _, err := child(parent, segment); err != nil {
+				return nil, err
+			}
+			return setChild(parent, segment, value)
+		})
+	case PatchMove:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("parsePointer(from): %w", err)
+		}
+		if op.From == op.Path {
+			return document, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") || len(from) == 0 {
+			return nil, fmt.Errorf("can not move '%s' into its child '%s'", op.From, op.Path)
+		}
+		value, err := valueAt(document, from)
+		if err != nil {
+			return nil, fmt.Errorf("valueAt(from): %w", err)
+		}
+		document, err = modifyAt(document, from, false, deleteChild)
+		if err != nil {
+			return nil, fmt.Errorf("remove from: %w", err)
+		}
+		return addAt(document, path, value)
+	case PatchCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("parsePointer(from): %w", err)
+		}
+		value, err := valueAt(document, from)
+		if err != nil {
+			return nil, fmt.Errorf("valueAt(from): %w", err)
+		}
+		return addAt(document, path, deepCopy(value))
+	case PatchTest:
+		value, err := valueAt(document, path)
+		if err != nil {
+			return nil, fmt.Errorf("valueAt: %w", err)
+		}
+		if !valuesEqual(value, op.Value) {
+			return nil, fmt.Errorf("value is %v, expected %v", value, op.Value)
+		}
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operation '%s'", op.Op)
+}
+
+// addAt adds the value into the document by the path.
+// The value is inserted into the lists, and set into the maps.
+func addAt(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return modifyAt(document, path, false, func(parent interface{}, segment string) (interface{}, error) {
+		return insertChild(parent, segment, value)
+	})
+}
+
+// Diff returns the JSON Patch (RFC 6902) that changes a into b.
+// The nested maps are compared by their keys, while the lists are replaced entirely.
+// The operations are sorted by the keys, so the same KeyValues give the same patch.
+func Diff(a KeyValue, b KeyValue) []PatchOp {
+	return diff("", a, b)
+}
+
+// diff returns the operations for the maps at the given pointer
+func diff(pointer string, a KeyValue, b KeyValue) []PatchOp {
+	ops := make([]PatchOp, 0)
+
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := pointer + "/" + escapePointer(key)
+		aValue, aOk := a[key]
+		bValue, bOk := b[key]
+
+		if !bOk {
+			ops = append(ops, PatchOp{Op: PatchRemove, Path: path})
+			continue
+		}
+		if !aOk {
+			ops = append(ops, PatchOp{Op: PatchAdd, Path: path, Value: deepCopy(bValue)})
+			continue
+		}
+
+		aNested, aIsMap := toKeyValue(aValue)
+		bNested, bIsMap := toKeyValue(bValue)
+		if aIsMap && bIsMap {
+			ops = append(ops, diff(path, aNested, bNested)...)
+			continue
+		}
+		if !valuesEqual(aValue, bValue) {
+			ops = append(ops, PatchOp{Op: PatchReplace, Path: path, Value: deepCopy(bValue)})
+		}
+	}
+
+	return ops
+}
@@ -0,0 +1,158 @@
+package key_value
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestPatchSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+// SetupTest prepares the KeyValue as it's decoded from json
+func (suite *TestPatchSuite) SetupTest() {
+	str := `{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"m~n":8,"nested":{"qty":5,"tags":["x"]}}`
+	kv, err := NewFromString(str)
+	suite.Require().NoError(err)
+
+	suite.kv = kv
+}
+
+func (suite *TestPatchSuite) TestPointer() {
+	// examples from RFC 6901
+	whole, err := suite.kv.Pointer("")
+	suite.Require().NoError(err)
+	suite.Require().Equal(suite.kv, whole)
+
+	value, err := suite.kv.Pointer("/foo")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]interface{}{"bar", "baz"}, value)
+
+	value, err = suite.kv.Pointer("/foo/0")
+	suite.Require().NoError(err)
+	suite.Require().Equal("bar", value)
+
+	value, err = suite.kv.Pointer("/")
+	suite.Require().NoError(err)
+	suite.Require().Equal(json.Number("0"), value)
+
+	value, err = suite.kv.Pointer("/a~1b")
+	suite.Require().NoError(err)
+	suite.Require().Equal(json.Number("1"), value)
+
+	value, err = suite.kv.Pointer("/m~0n")
+	suite.Require().NoError(err)
+	suite.Require().Equal(json.Number("8"), value)
+
+	_, err = suite.kv.Pointer("foo")
+	suite.Require().Error(err)
+	_, err = suite.kv.Pointer("/m~2n")
+	suite.Require().Error(err)
+	_, err = suite.kv.Pointer("/foo/2")
+	suite.Require().Error(err)
+
+	// the index has no leading zeros or signs
+	for _, pointer := range []string{"/foo/01", "/foo/+1", "/foo/-0", "/foo/00", "/foo/1e0"} {
+		_, err = suite.kv.Pointer(pointer)
+		suite.Require().Error(err, pointer)
+	}
+}
+
+func (suite *TestPatchSuite) TestApplyPatch() {
+	ops := []PatchOp{
+		{Op: PatchTest, Path: "/nested/qty", Value: uint64(5)},
+		{Op: PatchAdd, Path: "/foo/1", Value: "qux"},
+		{Op: PatchAdd, Path: "/foo/-", Value: "end"},
+		{Op: PatchRemove, Path: "/a~1b"},
+		{Op: PatchReplace, Path: "/nested/qty", Value: uint64(6)},
+		{Op: PatchMove, From: "/m~0n", Path: "/nested/moved"},
+		{Op: PatchCopy, From: "/nested/tags", Path: "/tags"},
+		{Op: PatchAdd, Path: "/nested/tags/0", Value: "w"},
+	}
+	suite.Require().NoError(suite.kv.ApplyPatch(ops))
+
+	foo, err := suite.kv.StringsValue("foo")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"bar", "qux", "baz", "end"}, foo)
+	suite.Require().False(suite.kv.Exist("a/b"))
+	suite.Require().False(suite.kv.Exist("m~n"))
+
+	qty, err := suite.kv.Uint64At("nested.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(6), qty)
+
+	moved, err := suite.kv.Uint64At("nested.moved")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(8), moved)
+
+	// the copy is not shared
+	tags, err := suite.kv.StringsValue("tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"x"}, tags)
+	nestedTags, err := suite.kv.StringsAt("nested.tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"w", "x"}, nestedTags)
+}
+
+func (suite *TestPatchSuite) TestFailedPatch() {
+	original := suite.kv.String()
+
+	invalid := [][]PatchOp{
+		{{Op: PatchTest, Path: "/nested/qty", Value: uint64(6)}},
+		{{Op: PatchAdd, Path: "/nested/note", Value: nil}},
+		{{Op: PatchAdd, Path: "/nested/note", Value: map[string]interface{}{"empty": nil}}},
+		{{Op: PatchReplace, Path: "/nested/missing", Value: "value"}},
+		{{Op: PatchRemove, Path: "/missing"}},
+		{{Op: PatchRemove, Path: ""}},
+		{{Op: PatchAdd, Path: "/foo/5", Value: "value"}},
+		{{Op: PatchAdd, Path: "/foo/01", Value: "value"}},
+		{{Op: PatchReplace, Path: "/foo/+1", Value: "value"}},
+		{{Op: PatchMove, From: "/nested", Path: "/nested/child"}},
+		{{Op: PatchReplace, Path: "", Value: "not a map"}},
+		{{Op: "unknown", Path: "/foo"}},
+		// the first operation is not applied, since the second fails
+		{
+			{Op: PatchRemove, Path: "/foo"},
+			{Op: PatchRemove, Path: "/missing"},
+		},
+	}
+	for i, ops := range invalid {
+		suite.Require().Error(suite.kv.ApplyPatch(ops), "patch %d", i)
+		suite.Require().Equal(original, suite.kv.String(), "patch %d", i)
+	}
+}
+
+func (suite *TestPatchSuite) TestDiff() {
+	target, err := NewFromString(`{"foo":["bar"],"":0,"c%d":3,"m~n":8,"nested":{"qty":5,"tags":["x"],"added":true},"new":"value"}`)
+	suite.Require().NoError(err)
+
+	ops := Diff(suite.kv, target)
+	expected := []PatchOp{
+		{Op: PatchRemove, Path: "/a~1b"},
+		{Op: PatchReplace, Path: "/c%d", Value: json.Number("3")},
+		{Op: PatchReplace, Path: "/foo", Value: []interface{}{"bar"}},
+		{Op: PatchAdd, Path: "/nested/added", Value: true},
+		{Op: PatchAdd, Path: "/new", Value: "value"},
+	}
+	suite.Require().Equal(expected, ops)
+
+	suite.Require().NoError(suite.kv.ApplyPatch(ops))
+	suite.Require().Empty(Diff(suite.kv, target))
+
+	// the numbers of different types are equal
+	numbers := KeyValue{"uint": uint64(5), "float": float64(5), "json": json.Number("5")}
+	sameNumbers := KeyValue{"uint": json.Number("5"), "float": uint64(5), "json": float64(5)}
+	suite.Require().Empty(Diff(numbers, sameNumbers))
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestPatch(t *testing.T) {
+	suite.Run(t, new(TestPatchSuite))
+}
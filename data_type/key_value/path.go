@@ -0,0 +1,645 @@
+package key_value
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The path is the list of the keys separated by a dot: "order.items.0.qty".
+// The segment of the path is either the key of the nested map,
+// or the index in the list.
+
+// PathSeparator separates the keys in the path
+const PathSeparator = "."
+
+// splitPath returns the segments of the dotted path
+func splitPath(path string) ([]string, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	segments := strings.Split(path, PathSeparator)
+	for i, segment := range segments {
+		if len(segment) == 0 {
+			return nil, fmt.Errorf("path '%s' has an empty segment at %d", path, i)
+		}
+	}
+
+	return segments, nil
+}
+
+// listIndex parses the segment as the index of the list with the given length.
+// As in RFC 6901, the index is "0" or the digits without the leading zero,
+// so "01", "+1" and "-0" are not the indexes.
+func listIndex(segment string, length int) (int, error) {
+	if !isListIndex(segment) {
+		return 0, fmt.Errorf("'%s' is not a list index", segment)
+	}
+	index, err := strconv.Atoi(segment)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a list index: %w", segment, err)
+	}
+	if index < 0 || index >= length {
+		return 0, fmt.Errorf("index %d out of range, list length is %d: %w", index, length, ErrKeyNotFound)
+	}
+
+	return index, nil
+}
+
+// isListIndex returns true if the segment is "0" or [1-9][0-9]*
+func isListIndex(segment string) bool {
+	if len(segment) == 0 || (segment[0] == '0' && len(segment) > 1) {
+		return false
+	}
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// child returns the element of the node by the segment.
+// The node is a map or a list.
+func child(node interface{}, segment string) (interface{}, error) {
+	switch container := node.(type) {
+	case KeyValue:
+		value, ok := container[segment]
+		if !ok {
+			return nil, notExist(segment)
+		}
+		return value, nil
+	case map[string]interface{}:
+		value, ok := container[segment]
+		if !ok {
+			return nil, notExist(segment)
+		}
+		return value, nil
+	case []interface{}:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return container[index], nil
+	case []KeyValue:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return container[index], nil
+	case []string:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return container[index], nil
+	}
+
+	return nil, fmt.Errorf("can not get '%s' from %T: %w", segment, node, ErrTypeMismatch)
+}
+
+// setChild sets the element of the node by the segment.
+// Returns the node with the element.
+func setChild(node interface{}, segment string, value interface{}) (interface{}, error) {
+	switch container := node.(type) {
+	case KeyValue:
+		container[segment] = value
+		return container, nil
+	case map[string]interface{}:
+		container[segment] = value
+		return container, nil
+	case []interface{}:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		container[index] = value
+		return container, nil
+	case []KeyValue:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		nested, ok := toKeyValue(value)
+		if !ok {
+			return nil, fmt.Errorf("kv-value list can not have %T", value)
+		}
+		container[index] = nested
+		return container, nil
+	case []string:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("string list can not have %T", value)
+		}
+		container[index] = str
+		return container, nil
+	}
+
+	return nil, fmt.Errorf("can not set '%s' in %T", segment, node)
+}
+
+// deleteChild removes the element of the node by the segment.
+// Returns the node without the element.
+// The list is shrunk, so the returned node must replace the original.
+func deleteChild(node interface{}, segment string) (interface{}, error) {
+	switch container := node.(type) {
+	case KeyValue:
+		if _, ok := container[segment]; !ok {
+			return nil, notExist(segment)
+		}
+		delete(container, segment)
+		return container, nil
+	case map[string]interface{}:
+		if _, ok := container[segment]; !ok {
+			return nil, notExist(segment)
+		}
+		delete(container, segment)
+		return container, nil
+	case []interface{}:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return append(container[:index:index], container[index+1:]...), nil
+	case []KeyValue:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return append(container[:index:index], container[index+1:]...), nil
+	case []string:
+		index, err := listIndex(segment, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return append(container[:index:index], container[index+1:]...), nil
+	}
+
+	return nil, fmt.Errorf("can not delete '%s' from %T", segment, node)
+}
+
+// insertChild inserts the element into the list before the index given by the segment.
+// The index equal to the list length, or "-" appends the element.
+// For the map, it's the same as setChild.
+// Returns the node with the element.
+func insertChild(node interface{}, segment string, value interface{}) (interface{}, error) {
+	if isMap(node) {
+		return setChild(node, segment, value)
+	}
+
+	list, ok := toList(node)
+	if !ok {
+		return nil, fmt.Errorf("can not insert '%s' into %T", segment, node)
+	}
+	index := len(list)
+	if segment != "-" {
+		var err error
+		// the index past the last element is allowed
+		index, err = listIndex(segment, len(list)+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch container := node.(type) {
+	case []KeyValue:
+		nested, ok := toKeyValue(value)
+		if !ok {
+			return nil, fmt.Errorf("kv-value list can not have %T", value)
+		}
+		inserted := make([]KeyValue, 0, len(container)+1)
+		inserted = append(inserted, container[:index]...)
+		inserted = append(inserted, nested)
+		return append(inserted, container[index:]...), nil
+	case []string:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("string list can not have %T", value)
+		}
+		inserted := make([]string, 0, len(container)+1)
+		inserted = append(inserted, container[:index]...)
+		inserted = append(inserted, str)
+		return append(inserted, container[index:]...), nil
+	}
+
+	inserted := make([]interface{}, 0, len(list)+1)
+	inserted = append(inserted, list[:index]...)
+	inserted = append(inserted, value)
+	return append(inserted, list[index:]...), nil
+}
+
+// toKeyValue returns the value as KeyValue if it's a map
+func toKeyValue(value interface{}) (KeyValue, bool) {
+	switch nested := value.(type) {
+	case KeyValue:
+		return nested, true
+	case map[string]interface{}:
+		return nested, true
+	}
+
+	return nil, false
+}
+
+// isMap returns true if the node is a KeyValue or a golang map
+func isMap(node interface{}) bool {
+	_, ok := toKeyValue(node)
+	return ok
+}
+
+// noNil checks that the value and its nested values are not nil.
+func noNil(value interface{}) error {
+	if value == nil {
+		return fmt.Errorf("value is %w", ErrNilValue)
+	}
+
+	if nested, ok := toKeyValue(value); ok {
+		for key, nestedValue := range nested {
+			if err := noNil(nestedValue); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		return nil
+	}
+
+	if list, ok := value.([]interface{}); ok {
+		for i, element := range list {
+			if err := noNil(element); err != nil {
+				return fmt.Errorf("%d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deepCopy returns the copy of the value.
+// The nested maps and lists are copied too, so changing the copy doesn't change the value.
+func deepCopy(value interface{}) interface{} {
+	switch original := value.(type) {
+	case KeyValue:
+		return original.deepCopy()
+	case map[string]interface{}:
+		return map[string]interface{}(KeyValue(original).deepCopy())
+	case []interface{}:
+		copied := make([]interface{}, len(original))
+		for i, element := range original {
+			copied[i] = deepCopy(element)
+		}
+		return copied
+	case []KeyValue:
+		copied := make([]KeyValue, len(original))
+		for i, element := range original {
+			copied[i] = element.deepCopy()
+		}
+		return copied
+	case []string:
+		copied := make([]string, len(original))
+		copy(copied, original)
+		return copied
+	case []byte:
+		copied := make([]byte, len(original))
+		copy(copied, original)
+		return copied
+	case *big.Int:
+		if original == nil {
+			return original
+		}
+		return new(big.Int).Set(original)
+	case *big.Float:
+		if original == nil {
+			return original
+		}
+		return new(big.Float).Copy(original)
+	}
+
+	// the other slices, like []uint64, are copied element by element
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() == reflect.Slice && !reflected.IsNil() {
+		copied := reflect.MakeSlice(reflected.Type(), reflected.Len(), reflected.Len())
+		for i := 0; i < reflected.Len(); i++ {
+			element := deepCopy(reflected.Index(i).Interface())
+			if element != nil {
+				copied.Index(i).Set(reflect.ValueOf(element))
+			}
+		}
+		return copied.Interface()
+	}
+
+	return value
+}
+
+// deepCopy returns the copy of k with the copied nested values
+func (k KeyValue) deepCopy() KeyValue {
+	if k == nil {
+		return nil
+	}
+
+	copied := make(KeyValue, len(k))
+	for key, value := range k {
+		copied[key] = deepCopy(value)
+	}
+
+	return copied
+}
+
+// valueAt walks the node by the segments and returns the found value
+func valueAt(node interface{}, segments []string) (interface{}, error) {
+	for i, segment := range segments {
+		value, err := child(node, segment)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Join(segments[:i+1], PathSeparator), err)
+		}
+		node = value
+	}
+
+	return node, nil
+}
+
+// leafFunc changes the parent by the last segment of the path.
+// Returns the changed parent.
+type leafFunc func(parent interface{}, segment string) (interface{}, error)
+
+// modifyAt walks the node by the segments and calls leaf with the parent of the last segment.
+// If create is true, then the missing maps are created.
+// Returns the changed node.
+func modifyAt(node interface{}, segments []string, create bool, leaf leafFunc) (interface{}, error) {
+	if len(segments) == 1 {
+		changed, err := leaf(node, segments[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", segments[0], err)
+		}
+		return changed, nil
+	}
+
+	next, err := child(node, segments[0])
+	if err != nil {
+		if !create || !isMap(node) {
+			return nil, fmt.Errorf("%s: %w", segments[0], err)
+		}
+		next = New()
+	}
+
+	changed, err := modifyAt(next, segments[1:], create, leaf)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%w", segments[0], err)
+	}
+
+	// the lists could be reallocated, so the parent must keep the changed one
+	node, err = setChild(node, segments[0], changed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", segments[0], err)
+	}
+
+	return node, nil
+}
+
+// leafAt returns the value by the path as a KeyValue of one element,
+// and the key of the element.
+// The returned KeyValue is used to convert the value with the same rules as the top level values.
+func (k KeyValue) leafAt(path string) (KeyValue, string, error) {
+	segments, err := splitPath(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("splitPath: %w", err)
+	}
+
+	value, err := valueAt(k, segments)
+	if err != nil {
+		return nil, "", fmt.Errorf("valueAt: %w", err)
+	}
+
+	key := segments[len(segments)-1]
+	return KeyValue{key: value}, key, nil
+}
+
+// ValueAt returns the raw value by the dotted path
+func (k KeyValue) ValueAt(path string) (interface{}, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf[key], nil
+}
+
+// ExistAt returns true if the dotted path has a value
+func (k KeyValue) ExistAt(path string) bool {
+	_, err := k.ValueAt(path)
+	return err == nil
+}
+
+// Uint64At returns the value by the dotted path as an uint64
+func (k KeyValue) Uint64At(path string) (uint64, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return leaf.Uint64Value(key)
+}
+
+// Float64At returns the value by the dotted path as a float64
+func (k KeyValue) Float64At(path string) (float64, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return leaf.Float64Value(key)
+}
+
+// BoolAt returns the value by the dotted path as a boolean
+func (k KeyValue) BoolAt(path string) (bool, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return false, err
+	}
+
+	return leaf.BoolValue(key)
+}
+
+// BigIntAt returns the value by the dotted path as a large number
+func (k KeyValue) BigIntAt(path string) (*big.Int, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.BigIntValue(key)
+}
+
+// StringAt returns the value by the dotted path as a string
+func (k KeyValue) StringAt(path string) (string, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return "", err
+	}
+
+	return leaf.StringValue(key)
+}
+
+// StringsAt returns the value by the dotted path as a list of strings
+func (k KeyValue) StringsAt(path string) ([]string, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.StringsValue(key)
+}
+
+// NestedAt returns the value by the dotted path as a KeyValue
+func (k KeyValue) NestedAt(path string) (KeyValue, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.NestedValue(key)
+}
+
+// NestedListAt returns the value by the dotted path as a list of KeyValue
+func (k KeyValue) NestedListAt(path string) ([]KeyValue, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.NestedListValue(key)
+}
+
+// Int64At returns the value by the dotted path as an int64
+func (k KeyValue) Int64At(path string) (int64, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return leaf.Int64Value(key)
+}
+
+// Int32At returns the value by the dotted path as an int32
+func (k KeyValue) Int32At(path string) (int32, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return leaf.Int32Value(key)
+}
+
+// Uint32At returns the value by the dotted path as an uint32
+func (k KeyValue) Uint32At(path string) (uint32, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return leaf.Uint32Value(key)
+}
+
+// DurationAt returns the value by the dotted path as a duration
+func (k KeyValue) DurationAt(path string) (time.Duration, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return leaf.DurationValue(key)
+}
+
+// TimeAt returns the value by the dotted path as a time
+func (k KeyValue) TimeAt(path string) (time.Time, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return leaf.TimeValue(key)
+}
+
+// BytesAt returns the value by the dotted path as the bytes
+func (k KeyValue) BytesAt(path string) ([]byte, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.BytesValue(key)
+}
+
+// BigFloatAt returns the value by the dotted path as the big float
+func (k KeyValue) BigFloatAt(path string) (*big.Float, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.BigFloatValue(key)
+}
+
+// Uint64sAt returns the value by the dotted path as a list of uint64
+func (k KeyValue) Uint64sAt(path string) ([]uint64, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.Uint64sValue(key)
+}
+
+// Float64sAt returns the value by the dotted path as a list of float64
+func (k KeyValue) Float64sAt(path string) ([]float64, error) {
+	leaf, key, err := k.leafAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf.Float64sValue(key)
+}
+
+// SetAt sets the value by the dotted path.
+// The missing intermediate values are created as KeyValue.
+// The list elements could be replaced, but the list is not extended.
+// The value and its nested values can not be nil.
+func (k KeyValue) SetAt(path string, value interface{}) error {
+	if err := noNil(value); err != nil {
+		return fmt.Errorf("value of '%s': %w", path, err)
+	}
+	segments, err := splitPath(path)
+	if err != nil {
+		return fmt.Errorf("splitPath: %w", err)
+	}
+
+	leaf := func(parent interface{}, segment string) (interface{}, error) {
+		return setChild(parent, segment, value)
+	}
+	if _, err := modifyAt(k, segments, true, leaf); err != nil {
+		return fmt.Errorf("modifyAt: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAt removes the value by the dotted path.
+// If the value is a list element, then the list is shrunk.
+func (k KeyValue) DeleteAt(path string) error {
+	segments, err := splitPath(path)
+	if err != nil {
+		return fmt.Errorf("splitPath: %w", err)
+	}
+
+	if _, err := modifyAt(k, segments, false, deleteChild); err != nil {
+		return fmt.Errorf("modifyAt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package key_value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestPathSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+// SetupTest prepares the nested KeyValue as it's decoded from json
+func (suite *TestPathSuite) SetupTest() {
+	str := `{"order":{"id":"o_1","paid":true,"price":1.5,"items":[{"qty":2,"tags":["a","b"]},{"qty":3}],"total":"123456789012345678901234567890"},"big":123456789012345678901234567890}`
+	kv, err := NewFromString(str)
+	suite.Require().NoError(err)
+
+	suite.kv = kv
+}
+
+func (suite *TestPathSuite) TestGetters() {
+	qty, err := suite.kv.Uint64At("order.items.1.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(3), qty)
+
+	id, err := suite.kv.StringAt("order.id")
+	suite.Require().NoError(err)
+	suite.Require().Equal("o_1", id)
+
+	paid, err := suite.kv.BoolAt("order.paid")
+	suite.Require().NoError(err)
+	suite.Require().True(paid)
+
+	price, err := suite.kv.Float64At("order.price")
+	suite.Require().NoError(err)
+	suite.Require().Equal(1.5, price)
+
+	big, err := suite.kv.BigIntAt("big")
+	suite.Require().NoError(err)
+	suite.Require().Equal("123456789012345678901234567890", big.String())
+
+	tags, err := suite.kv.StringsAt("order.items.0.tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a", "b"}, tags)
+
+	tag, err := suite.kv.StringAt("order.items.0.tags.1")
+	suite.Require().NoError(err)
+	suite.Require().Equal("b", tag)
+
+	item, err := suite.kv.NestedAt("order.items.0")
+	suite.Require().NoError(err)
+	suite.Require().True(item.Exist("qty"))
+
+	items, err := suite.kv.NestedListAt("order.items")
+	suite.Require().NoError(err)
+	suite.Require().Len(items, 2)
+
+	suite.Require().True(suite.kv.ExistAt("order.items.1"))
+	suite.Require().False(suite.kv.ExistAt("order.items.2"))
+	suite.Require().False(suite.kv.ExistAt("order.id.value"))
+	suite.Require().False(suite.kv.ExistAt("order..id"))
+	suite.Require().False(suite.kv.ExistAt(""))
+
+	// the value exists, but it has another type
+	_, err = suite.kv.Uint64At("order.id")
+	suite.Require().Error(err)
+	_, err = suite.kv.Uint64At("order.items.-1.qty")
+	suite.Require().Error(err)
+	_, err = suite.kv.Uint64At("order.items.first.qty")
+	suite.Require().Error(err)
+}
+
+func (suite *TestPathSuite) TestSetAt() {
+	suite.Require().NoError(suite.kv.SetAt("order.items.1.qty", uint64(4)))
+	qty, err := suite.kv.Uint64At("order.items.1.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(4), qty)
+
+	// the intermediate values are created
+	suite.Require().NoError(suite.kv.SetAt("shipping.address.city", "Paris"))
+	city, err := suite.kv.StringAt("shipping.address.city")
+	suite.Require().NoError(err)
+	suite.Require().Equal("Paris", city)
+	_, err = suite.kv.NestedValue("shipping")
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.kv.SetAt("order.items.0.tags.0", "c"))
+	tags, err := suite.kv.StringsAt("order.items.0.tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"c", "b"}, tags)
+
+	// the list is not extended
+	suite.Require().Error(suite.kv.SetAt("order.items.2.qty", uint64(1)))
+	// the string has no nested values
+	suite.Require().Error(suite.kv.SetAt("order.id.value", "o_2"))
+	// no nil values
+	suite.Require().ErrorIs(suite.kv.SetAt("order.note", nil), ErrNilValue)
+	suite.Require().ErrorIs(suite.kv.SetAt("order.note", map[string]interface{}{"b": nil}), ErrNilValue)
+	suite.Require().ErrorIs(suite.kv.SetAt("order.note", []interface{}{"a", nil}), ErrNilValue)
+	suite.Require().False(suite.kv.ExistAt("order.note"))
+	suite.Require().Error(suite.kv.SetAt("", "empty"))
+}
+
+func (suite *TestPathSuite) TestDeleteAt() {
+	suite.Require().NoError(suite.kv.DeleteAt("order.items.0"))
+	qty, err := suite.kv.Uint64At("order.items.0.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(3), qty)
+	suite.Require().False(suite.kv.ExistAt("order.items.1"))
+
+	suite.Require().NoError(suite.kv.DeleteAt("order.paid"))
+	suite.Require().False(suite.kv.ExistAt("order.paid"))
+
+	suite.Require().Error(suite.kv.DeleteAt("order.paid"))
+	suite.Require().Error(suite.kv.DeleteAt("order.items.5"))
+
+	suite.Require().NoError(suite.kv.DeleteAt("big"))
+	suite.Require().False(suite.kv.Exist("big"))
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestPath(t *testing.T) {
+	suite.Run(t, new(TestPathSuite))
+}
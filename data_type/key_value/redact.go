@@ -0,0 +1,240 @@
+package key_value
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RedactAction defines what Redactor does with the matched value
+type RedactAction uint8
+
+const (
+	// RedactMask replaces the value with RedactedValue
+	RedactMask RedactAction = iota
+	// RedactHash replaces the value with its hash, so the equal values could be correlated in the logs.
+	// The hash is "hash:" followed by the first 16 hex digits of SHA-256,
+	// or HMAC-SHA256 if the Redactor has the hash key.
+	RedactHash
+	// RedactDrop removes the value
+	RedactDrop
+)
+
+// RedactedValue replaces the masked values
+const RedactedValue = "[redacted]"
+
+// DefaultRedactor is used by the messages to hide the sensitive parameters in the logs.
+// It masks the well-known keys of the passwords, secrets, access tokens and private keys.
+// The patterns are narrow, so the business fields like "tokens" or "token_address" are kept.
+// Add the rules of the application to it:
+//
+//	key_value.DefaultRedactor.Add("card.number", key_value.RedactHash)
+var DefaultRedactor = newDefaultRedactor()
+
+// redactRule is the pattern split by PathSeparator
+type redactRule struct {
+	segments []string
+	isPath   bool // Match the path from the top level, otherwise match the key at any level
+	action   RedactAction
+}
+
+// Redactor hides the sensitive values of KeyValue, for example before logging it.
+//
+// The values are matched by the patterns, case-insensitive:
+//   - the exact key, like "password", matches the key at any level.
+//   - the glob, like "*_token", matches the key at any level.
+//     The syntax is the same as in path.Match.
+//   - the dotted path, like "user.credentials" or "items.*.card",
+//     matches the path from the top level. Each segment could be a glob,
+//     and the list elements are matched by their indexes.
+//
+// The keys of the flattened KeyValue, like "user.password", are matched as the paths.
+//
+// The first matching rule is applied, and the redacted value is not checked further.
+// Redactor is safe to use from multiple goroutines.
+type Redactor struct {
+	mu      sync.RWMutex
+	rules   []redactRule
+	hashKey []byte
+}
+
+// NewRedactor returns the redactor without the rules
+func NewRedactor() *Redactor {
+	return &Redactor{rules: make([]redactRule, 0)}
+}
+
+func newDefaultRedactor() *Redactor {
+	r := NewRedactor()
+	for _, pattern := range []string{"password", "*_password", "passwd", "secret", "*_secret",
+		"access_token", "refresh_token", "id_token", "auth_token",
+		"authorization", "api_key", "apikey", "private_key", "credentials"} {
+		if err := r.Add(pattern, RedactMask); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
+
+// Add registers the rule.
+// Returns an error if the pattern is not valid.
+func (r *Redactor) Add(pattern string, action RedactAction) error {
+	if action > RedactDrop {
+		return fmt.Errorf("unsupported redact action %d", action)
+	}
+	segments, err := splitPath(strings.ToLower(pattern))
+	if err != nil {
+		return fmt.Errorf("splitPath: %w", err)
+	}
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("pattern '%s': %w", pattern, err)
+		}
+	}
+
+	r.mu.Lock()
+	r.rules = append(r.rules, redactRule{segments: segments, isPath: len(segments) > 1, action: action})
+	r.mu.Unlock()
+
+	return nil
+}
+
+// SetHashKey sets the key of HMAC used by RedactHash.
+// Without the key, the short values like the passwords could be guessed by their hashes.
+func (r *Redactor) SetHashKey(key []byte) {
+	r.mu.Lock()
+	r.hashKey = append([]byte{}, key...)
+	r.mu.Unlock()
+}
+
+// Redact returns the copy of kv with the matched values redacted.
+// The kv is not changed.
+func (r *Redactor) Redact(kv KeyValue) KeyValue {
+	if kv == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	redacted, _ := r.redact(kv, nil, false)
+	return redacted.(KeyValue)
+}
+
+// match returns the action of the first rule matching the value by the path.
+// The path segments are in the lower case.
+// The key with the dots, like "user.password" of the flattened KeyValue,
+// is also matched as the path, so the key pattern matches its last component.
+func (r *Redactor) match(segments []string, isKey bool) (RedactAction, bool) {
+	key := segments[len(segments)-1]
+	var expanded []string
+	if isKey && strings.Contains(key, PathSeparator) {
+		expanded = append(segments[:len(segments)-1:len(segments)-1], strings.Split(key, PathSeparator)...)
+	}
+
+	for _, rule := range r.rules {
+		if rule.isPath {
+			if matchSegments(rule.segments, segments) ||
+				(expanded != nil && matchSegments(rule.segments, expanded)) {
+				return rule.action, true
+			}
+			continue
+		}
+		if !isKey {
+			continue
+		}
+		if matched, _ := path.Match(rule.segments[0], key); matched {
+			return rule.action, true
+		}
+		if expanded != nil {
+			if matched, _ := path.Match(rule.segments[0], expanded[len(expanded)-1]); matched {
+				return rule.action, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func matchSegments(patterns []string, segments []string) bool {
+	if len(patterns) != len(segments) {
+		return false
+	}
+	for i, pattern := range patterns {
+		if matched, _ := path.Match(pattern, segments[i]); !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redact returns the copy of the value with the nested values redacted.
+// The isKey is false if the value is the list element.
+// Returns false if the value is dropped.
+func (r *Redactor) redact(value interface{}, segments []string, isKey bool) (interface{}, bool) {
+	if len(segments) > 0 {
+		if action, ok := r.match(segments, isKey); ok {
+			switch action {
+			case RedactDrop:
+				return nil, false
+			case RedactHash:
+				return r.hash(value), true
+			}
+			return RedactedValue, true
+		}
+	}
+
+	if nested, ok := toKeyValue(value); ok {
+		redacted := make(KeyValue, len(nested))
+		for key, nestedValue := range nested {
+			nestedSegments := append(segments[:len(segments):len(segments)], strings.ToLower(key))
+			if redactedValue, ok := r.redact(nestedValue, nestedSegments, true); ok {
+				redacted[key] = redactedValue
+			}
+		}
+		if _, ok := value.(map[string]interface{}); ok {
+			return map[string]interface{}(redacted), true
+		}
+		return redacted, true
+	}
+
+	if list, ok := toList(value); ok {
+		redacted := make([]interface{}, 0, len(list))
+		for i, element := range list {
+			elementSegments := append(segments[:len(segments):len(segments)], strconv.Itoa(i))
+			if redactedValue, ok := r.redact(element, elementSegments, false); ok {
+				redacted = append(redacted, redactedValue)
+			}
+		}
+		return redacted, true
+	}
+
+	return deepCopy(value), true
+}
+
+// hash returns the short hash of the value
+func (r *Redactor) hash(value interface{}) string {
+	var h hash.Hash
+	if len(r.hashKey) > 0 {
+		h = hmac.New(sha256.New, r.hashKey)
+	} else {
+		h = sha256.New()
+	}
+
+	if s, ok := value.(string); ok {
+		h.Write([]byte(s))
+	} else {
+		encoded, _ := json.Marshal(normalize(value))
+		h.Write(encoded)
+	}
+
+	return "hash:" + hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,175 @@
+package key_value
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestRedactSuite struct {
+	suite.Suite
+	kv KeyValue
+}
+
+func (suite *TestRedactSuite) SetupTest() {
+	kv, err := NewFromString(`{"name":"sds","Password":"secret","api_key":"k",
+		"user":{"name":"a","password":"p","card":{"number":"4242","cvv":"123"}},
+		"items":[{"card":"1111","qty":1},{"card":"2222","qty":2}],"tags":["a","b"]}`)
+	suite.Require().NoError(err)
+	suite.kv = kv
+}
+
+func (suite *TestRedactSuite) TestPatterns() {
+	r := NewRedactor()
+	suite.Require().NoError(r.Add("password", RedactMask))
+	suite.Require().NoError(r.Add("api_*", RedactDrop))
+	suite.Require().NoError(r.Add("user.card.number", RedactHash))
+	suite.Require().NoError(r.Add("items.*.card", RedactMask))
+	suite.Require().NoError(r.Add("tags.1", RedactDrop))
+
+	redacted := r.Redact(suite.kv)
+
+	// the exact key matches at any level, case-insensitive
+	suite.Require().Equal(RedactedValue, redacted["Password"])
+	passwordAt, err := redacted.StringAt("user.password")
+	suite.Require().NoError(err)
+	suite.Require().Equal(RedactedValue, passwordAt)
+
+	// the glob
+	suite.Require().False(redacted.Exist("api_key"))
+
+	// the dotted path matches from the top level only
+	number, err := redacted.StringAt("user.card.number")
+	suite.Require().NoError(err)
+	suite.Require().True(strings.HasPrefix(number, "hash:"))
+	cvv, err := redacted.StringAt("user.card.cvv")
+	suite.Require().NoError(err)
+	suite.Require().Equal("123", cvv)
+
+	card, err := redacted.StringAt("items.1.card")
+	suite.Require().NoError(err)
+	suite.Require().Equal(RedactedValue, card)
+	qty, err := redacted.Uint64At("items.1.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(2), qty)
+
+	tags, err := redacted.StringsValue("tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a"}, tags)
+
+	// the original is not changed
+	suite.Require().Equal("secret", suite.kv["Password"])
+	suite.Require().True(suite.kv.ExistAt("user.card.number"))
+	suite.Require().True(suite.kv.ExistAt("tags.1"))
+
+	suite.Require().Nil(r.Redact(nil))
+}
+
+func (suite *TestRedactSuite) TestFlattened() {
+	r := NewRedactor()
+	suite.Require().NoError(r.Add("password", RedactMask))
+	suite.Require().NoError(r.Add("user.card.number", RedactHash))
+	suite.Require().NoError(r.Add("items.*.card", RedactDrop))
+
+	flat, err := suite.kv.Flatten()
+	suite.Require().NoError(err)
+	redacted := r.Redact(flat)
+
+	// the key pattern matches the last component of the flattened key
+	suite.Require().Equal(RedactedValue, redacted["user.password"])
+	suite.Require().Equal(RedactedValue, redacted["Password"])
+	suite.Require().Equal("a", redacted["user.name"])
+
+	// the path pattern matches the whole flattened key
+	suite.Require().True(strings.HasPrefix(redacted["user.card.number"].(string), "hash:"))
+	suite.Require().Equal("123", redacted["user.card.cvv"])
+	suite.Require().False(redacted.Exist("items.0.card"))
+	suite.Require().False(redacted.Exist("items.1.card"))
+	suite.Require().True(redacted.Exist("items.0.qty"))
+
+	// the default rules hide the flattened secrets
+	defaults := DefaultRedactor.Redact(KeyValue{"user.password": "p", "db.api_key": "k", "user.name": "a"})
+	suite.Require().Equal(KeyValue{"user.password": RedactedValue, "db.api_key": RedactedValue, "user.name": "a"}, defaults)
+}
+
+func (suite *TestRedactSuite) TestFirstRule() {
+	r := NewRedactor()
+	suite.Require().NoError(r.Add("user", RedactMask))
+	suite.Require().NoError(r.Add("user.name", RedactDrop))
+
+	// the redacted value is not checked further
+	redacted := r.Redact(suite.kv)
+	suite.Require().Equal(RedactedValue, redacted["user"])
+}
+
+func (suite *TestRedactSuite) TestHash() {
+	r := NewRedactor()
+	suite.Require().NoError(r.Add("*card*", RedactHash))
+
+	// the equal values have the same hash
+	first := r.Redact(KeyValue{"card": "4242", "nested": KeyValue{"card": "4242"}})
+	suite.Require().Equal(first["card"], first["nested"].(KeyValue)["card"])
+	second := r.Redact(KeyValue{"card": "4243"})
+	suite.Require().NotEqual(first["card"], second["card"])
+
+	// the whole map is hashed
+	hashed := r.Redact(suite.kv)
+	suite.Require().IsType("", hashed["user"].(map[string]interface{})["card"])
+
+	// the hash key changes the hash
+	r.SetHashKey([]byte("key"))
+	keyed := r.Redact(KeyValue{"card": "4242"})
+	suite.Require().NotEqual(first["card"], keyed["card"])
+}
+
+func (suite *TestRedactSuite) TestInvalid() {
+	r := NewRedactor()
+	suite.Require().Error(r.Add("[", RedactMask))
+	suite.Require().Error(r.Add("user..name", RedactMask))
+	suite.Require().Error(r.Add("", RedactMask))
+	suite.Require().Error(r.Add("name", RedactDrop+1))
+}
+
+func (suite *TestRedactSuite) TestDefault() {
+	redacted := DefaultRedactor.Redact(KeyValue{"password": "a", "access_token": "b", "Authorization": "c", "name": "d",
+		"client_secret": "e", "new_password": "f"})
+
+	suite.Require().Equal(KeyValue{
+		"password":      RedactedValue,
+		"access_token":  RedactedValue,
+		"Authorization": RedactedValue,
+		"name":          "d",
+		"client_secret": RedactedValue,
+		"new_password":  RedactedValue,
+	}, redacted)
+
+	// the business fields are not hidden
+	business := KeyValue{"token_address": "0x1", "tokens": []interface{}{"a"}, "secretary": "b", "credential_type": "c"}
+	suite.Require().Equal(business, DefaultRedactor.Redact(business))
+}
+
+func (suite *TestRedactSuite) TestConcurrency() {
+	r := NewRedactor()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			suite.NoError(r.Add("password", RedactMask))
+			suite.Equal(RedactedValue, r.Redact(suite.kv)["Password"])
+		}()
+	}
+	wg.Wait()
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestRedact(t *testing.T) {
+	suite.Run(t, new(TestRedactSuite))
+}
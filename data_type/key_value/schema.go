@@ -0,0 +1,521 @@
+package key_value
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValueKind is the type of the value in KeyValue.
+// The kinds match the getters of KeyValue.
+type ValueKind string
+
+const (
+	KindAny        ValueKind = ""            // Any value
+	KindUint64     ValueKind = "uint64"      // Uint64Value
+	KindInt64      ValueKind = "int64"       // Int64Value, the strings are not accepted
+	KindFloat64    ValueKind = "float64"     // Float64Value
+	KindBigNumber  ValueKind = "big_number"  // BigIntValue
+	KindString     ValueKind = "string"      // StringValue
+	KindStrings    ValueKind = "strings"     // StringsValue
+	KindBool       ValueKind = "bool"        // BoolValue
+	KindNested     ValueKind = "nested"      // NestedValue
+	KindNestedList ValueKind = "nested_list" // NestedListValue
+)
+
+// Schema describes the expected value of KeyValue.
+// The zero values of the fields mean no restriction.
+//
+// The schema of the parameters is the KindNested schema:
+//
+//	schema := &key_value.Schema{
+//		Kind:     key_value.KindNested,
+//		Required: []string{"amount"},
+//		Properties: map[string]*key_value.Schema{
+//			"amount": {Kind: key_value.KindUint64, Minimum: key_value.Limit(1)},
+//		},
+//	}
+type Schema struct {
+	Kind ValueKind
+
+	// KindNested restrictions
+	Required           []string
+	Properties         map[string]*Schema
+	DisallowAdditional bool // If true, only the keys in Properties are allowed
+
+	// KindNestedList restrictions.
+	// Items is the schema of each element of the list, its kind is KindNested.
+	Items *Schema
+
+	// KindStrings and KindNestedList restrictions
+	MinItems *uint64
+	MaxItems *uint64
+
+	// KindUint64, KindInt64, KindFloat64 and KindBigNumber restrictions
+	Minimum *big.Float
+	Maximum *big.Float
+
+	// KindString restrictions; also applied on each element of KindStrings
+	MinLength *uint64 // The number of characters, not bytes
+	MaxLength *uint64
+	Pattern   string // Regular expression in the golang regexp syntax
+	pattern   *regexp.Regexp
+
+	// Enum lists the allowed values. The numbers are compared by the value.
+	Enum []interface{}
+}
+
+// Limit returns the number to be used as a Schema range
+func Limit(number float64) *big.Float {
+	return big.NewFloat(number)
+}
+
+// Count returns the number to be used as a Schema length or items restriction
+func Count(number uint64) *uint64 {
+	return &number
+}
+
+// FieldError is the failed validation of one value
+type FieldError struct {
+	Path    string // Dotted path of the value, empty for the whole KeyValue
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationError lists all values that failed the validation
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Error()
+	}
+
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
+}
+
+// validation collects the errors during the validation
+type validation struct {
+	errors []FieldError
+}
+
+func (v *validation) fail(path string, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// joinPath returns the dotted path of the key in the parent
+func joinPath(parent string, key string) string {
+	if len(parent) == 0 {
+		return key
+	}
+	return parent + PathSeparator + key
+}
+
+// Validate checks the KeyValue against the schema.
+// If the schema kind is KindAny, then it's treated as KindNested.
+// Returns *ValidationError with all failed values.
+func (s *Schema) Validate(kv KeyValue) error {
+	schema := *s
+	if schema.Kind == KindAny {
+		schema.Kind = KindNested
+	}
+	if schema.Kind != KindNested {
+		return fmt.Errorf("kv-value can not be validated by %s schema", s.Kind)
+	}
+
+	v := &validation{}
+	schema.validateNested(v, "", kv)
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+
+	return nil
+}
+
+// validateNested checks the keys of the KeyValue
+func (s *Schema) validateNested(v *validation, path string, kv KeyValue) {
+	for _, key := range s.Required {
+		if !kv.Exist(key) {
+			v.fail(joinPath(path, key), "required")
+		}
+	}
+
+	keys := make([]string, 0, len(kv))
+	for key := range kv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		property, ok := s.Properties[key]
+		if !ok {
+			if s.DisallowAdditional {
+				v.fail(joinPath(path, key), "not allowed")
+			}
+			continue
+		}
+		property.validate(v, joinPath(path, key), kv, key)
+	}
+}
+
+// validate checks the value of the key in the KeyValue.
+// The value is converted by the KeyValue getter of the schema kind.
+func (s *Schema) validate(v *validation, path string, kv KeyValue, key string) {
+	if len(s.Enum) > 0 && !s.inEnum(kv[key]) {
+		v.fail(path, "value %v is not in %v", kv[key], s.Enum)
+	}
+
+	switch s.Kind {
+	case KindAny:
+		if kv[key] == nil {
+			v.fail(path, "nil value")
+		}
+	case KindUint64:
+		number, err := kv.Uint64Value(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateRange(v, path, new(big.Float).SetUint64(number))
+	case KindInt64:
+		if _, ok := kv[key].(string); ok {
+			v.fail(path, "expected %s: got string", s.Kind)
+			return
+		}
+		number, err := kv.Int64Value(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateRange(v, path, new(big.Float).SetInt64(number))
+	case KindFloat64:
+		number, err := kv.Float64Value(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateRange(v, path, big.NewFloat(number))
+	case KindBigNumber:
+		number, err := kv.BigIntValue(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateRange(v, path, new(big.Float).SetInt(number))
+	case KindString:
+		str, err := kv.StringValue(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateString(v, path, str)
+	case KindStrings:
+		list, err := kv.StringsValue(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateItems(v, path, len(list))
+		for i, str := range list {
+			s.validateString(v, joinPath(path, fmt.Sprint(i)), str)
+		}
+	case KindBool:
+		if _, err := kv.BoolValue(key); err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+		}
+	case KindNested:
+		nested, err := kv.NestedValue(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateNested(v, path, nested)
+	case KindNestedList:
+		list, err := kv.NestedListValue(key)
+		if err != nil {
+			v.fail(path, "expected %s: %v", s.Kind, err)
+			return
+		}
+		s.validateItems(v, path, len(list))
+		if s.Items == nil {
+			return
+		}
+		for i, nested := range list {
+			s.Items.validateNested(v, joinPath(path, fmt.Sprint(i)), nested)
+		}
+	default:
+		v.fail(path, "unsupported schema kind '%s'", s.Kind)
+	}
+}
+
+func (s *Schema) inEnum(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if valuesEqual(value, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateRange(v *validation, path string, number *big.Float) {
+	if s.Minimum != nil && number.Cmp(s.Minimum) < 0 {
+		v.fail(path, "%s is less than minimum %s", number.String(), s.Minimum.String())
+	}
+	if s.Maximum != nil && number.Cmp(s.Maximum) > 0 {
+		v.fail(path, "%s is more than maximum %s", number.String(), s.Maximum.String())
+	}
+}
+
+func (s *Schema) validateItems(v *validation, path string, length int) {
+	if s.MinItems != nil && uint64(length) < *s.MinItems {
+		v.fail(path, "has %d items, minimum is %d", length, *s.MinItems)
+	}
+	if s.MaxItems != nil && uint64(length) > *s.MaxItems {
+		v.fail(path, "has %d items, maximum is %d", length, *s.MaxItems)
+	}
+}
+
+func (s *Schema) validateString(v *validation, path string, str string) {
+	length := uint64(utf8.RuneCountInString(str))
+	if s.MinLength != nil && length < *s.MinLength {
+		v.fail(path, "length %d is less than minimum %d", length, *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(path, "length %d is more than maximum %d", length, *s.MaxLength)
+	}
+	if len(s.Pattern) == 0 {
+		return
+	}
+
+	pattern, err := s.compiledPattern()
+	if err != nil {
+		v.fail(path, "invalid pattern '%s': %v", s.Pattern, err)
+		return
+	}
+	if !pattern.MatchString(str) {
+		v.fail(path, "'%s' doesn't match pattern '%s'", str, s.Pattern)
+	}
+}
+
+// patterns caches the compiled patterns of the schemas declared in go
+var patterns sync.Map
+
+// compiledPattern returns the compiled Pattern.
+// The schema loaded by NewSchemaFromJSON has it compiled already,
+// the pattern of the schema declared in go is compiled once and cached.
+func (s *Schema) compiledPattern() (*regexp.Regexp, error) {
+	if s.pattern != nil && s.pattern.String() == s.Pattern {
+		return s.pattern, nil
+	}
+	if cached, ok := patterns.Load(s.Pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	pattern, err := regexp.Compile(s.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(s.Pattern, pattern)
+
+	return pattern, nil
+}
+
+// jsonSchema is the subset of the JSON Schema (draft 2020-12) keywords
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *uint64                `json:"minItems"`
+	MaxItems             *uint64                `json:"maxItems"`
+	Minimum              *json.Number           `json:"minimum"`
+	Maximum              *json.Number           `json:"maximum"`
+	MinLength            *uint64                `json:"minLength"`
+	MaxLength            *uint64                `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Enum                 []interface{}          `json:"enum"`
+}
+
+// unsupportedKeywords are the JSON Schema keywords that this package doesn't implement.
+// They are rejected, rather than silently ignored.
+var unsupportedKeywords = []string{
+	"$ref", "$defs", "allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+	"exclusiveMinimum", "exclusiveMaximum", "multipleOf", "const", "prefixItems",
+	"patternProperties", "dependentRequired", "dependentSchemas", "uniqueItems",
+}
+
+// NewSchemaFromJSON parses the JSON Schema (draft 2020-12).
+//
+// The supported keywords are type, properties, required, additionalProperties, items,
+// minItems, maxItems, minimum, maximum, minLength, maxLength, pattern and enum.
+// The types are mapped to the kinds:
+//   - "object" is KindNested
+//   - "array" of "string" items is KindStrings, "array" of "object" items is KindNestedList
+//   - "integer" is KindInt64, or KindBigNumber with "format": "big_number"
+//   - "number" is KindFloat64
+//   - "string" is KindString
+//   - "boolean" is KindBool
+//
+// The annotations such as title or description are ignored.
+func NewSchemaFromJSON(data []byte) (*Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("json.decoder: '%w'", err)
+	}
+	if err := checkKeywords("", raw); err != nil {
+		return nil, err
+	}
+
+	var parsed jsonSchema
+	decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("json.decoder: '%w'", err)
+	}
+
+	schema, err := parsed.schema("")
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// checkKeywords returns an error if the raw schema or its sub-schemas have unsupported keywords
+func checkKeywords(path string, raw map[string]interface{}) error {
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := raw[keyword]; ok {
+			return fmt.Errorf("schema '%s': unsupported keyword '%s'", path, keyword)
+		}
+	}
+
+	if items, ok := raw["items"].(map[string]interface{}); ok {
+		if err := checkKeywords(joinPath(path, "items"), items); err != nil {
+			return err
+		}
+	}
+	properties, _ := raw["properties"].(map[string]interface{})
+	for key, property := range properties {
+		nested, ok := property.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("schema '%s': property is %T, not a schema", joinPath(path, key), property)
+		}
+		if err := checkKeywords(joinPath(path, key), nested); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schema converts the JSON Schema into the Schema
+func (js *jsonSchema) schema(path string) (*Schema, error) {
+	s := &Schema{
+		Required:  js.Required,
+		MinItems:  js.MinItems,
+		MaxItems:  js.MaxItems,
+		MinLength: js.MinLength,
+		MaxLength: js.MaxLength,
+		Pattern:   js.Pattern,
+		Enum:      js.Enum,
+	}
+	if js.AdditionalProperties != nil {
+		s.DisallowAdditional = !*js.AdditionalProperties
+	}
+
+	for _, limit := range []struct {
+		number *json.Number
+		target **big.Float
+	}{{js.Minimum, &s.Minimum}, {js.Maximum, &s.Maximum}} {
+		if limit.number == nil {
+			continue
+		}
+		parsed, ok := new(big.Float).SetString(string(*limit.number))
+		if !ok {
+			return nil, fmt.Errorf("schema '%s': invalid limit %s", path, *limit.number)
+		}
+		*limit.target = parsed
+	}
+
+	switch js.Type {
+	case "":
+		s.Kind = KindAny
+	case "object":
+		s.Kind = KindNested
+	case "integer":
+		s.Kind = KindInt64
+		if js.Format == string(KindBigNumber) {
+			s.Kind = KindBigNumber
+		}
+	case "number":
+		s.Kind = KindFloat64
+	case "string":
+		s.Kind = KindString
+	case "boolean":
+		s.Kind = KindBool
+	case "array":
+		if js.Items == nil {
+			return nil, fmt.Errorf("schema '%s': array must have items", path)
+		}
+		switch js.Items.Type {
+		case "string":
+			s.Kind = KindStrings
+			// the string restrictions of the items are applied on each element
+			s.MinLength = js.Items.MinLength
+			s.MaxLength = js.Items.MaxLength
+			s.Pattern = js.Items.Pattern
+		case "object":
+			s.Kind = KindNestedList
+			items, err := js.Items.schema(joinPath(path, "items"))
+			if err != nil {
+				return nil, err
+			}
+			s.Items = items
+		default:
+			return nil, fmt.Errorf("schema '%s': array of '%s' is not supported", path, js.Items.Type)
+		}
+	default:
+		return nil, fmt.Errorf("schema '%s': type '%s' is not supported", path, js.Type)
+	}
+
+	// the pattern is compiled once, when the schema is loaded
+	if len(s.Pattern) > 0 {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("schema '%s': invalid pattern: %w", path, err)
+		}
+		s.pattern = pattern
+	}
+
+	if len(js.Properties) > 0 {
+		s.Properties = make(map[string]*Schema, len(js.Properties))
+		for key, property := range js.Properties {
+			propertySchema, err := property.schema(joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			s.Properties[key] = propertySchema
+		}
+	}
+
+	return s, nil
+}
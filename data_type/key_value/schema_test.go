@@ -0,0 +1,191 @@
+package key_value
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestSchemaSuite struct {
+	suite.Suite
+	schema *Schema
+}
+
+// SetupTest declares the schema of the order parameters
+func (suite *TestSchemaSuite) SetupTest() {
+	suite.schema = &Schema{
+		Kind:     KindNested,
+		Required: []string{"id", "amount", "items"},
+		Properties: map[string]*Schema{
+			"id":       {Kind: KindString, Pattern: `^ord-[0-9]+$`},
+			"amount":   {Kind: KindUint64, Minimum: Limit(1), Maximum: Limit(1000)},
+			"price":    {Kind: KindFloat64, Minimum: Limit(0)},
+			"supply":   {Kind: KindBigNumber},
+			"currency": {Kind: KindString, Enum: []interface{}{"USD", "EUR"}},
+			"paid":     {Kind: KindBool},
+			"tags":     {Kind: KindStrings, MaxItems: Count(2), MinLength: Count(1)},
+			"items": {
+				Kind:     KindNestedList,
+				MinItems: Count(1),
+				Items: &Schema{
+					Kind:     KindNested,
+					Required: []string{"qty"},
+					Properties: map[string]*Schema{
+						"qty": {Kind: KindUint64, Minimum: Limit(1)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (suite *TestSchemaSuite) TestValid() {
+	kv, err := NewFromString(`{"id":"ord-1","amount":5,"price":1.5,"supply":123456789012345678901234567890,
+		"currency":"EUR","paid":true,"tags":["a","b"],"items":[{"qty":1},{"qty":2}],"extra":"allowed"}`)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.schema.Validate(kv))
+
+	// additional keys are rejected on demand
+	suite.schema.DisallowAdditional = true
+	err = suite.schema.Validate(kv)
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "extra: not allowed")
+}
+
+func (suite *TestSchemaSuite) TestErrors() {
+	kv, err := NewFromString(`{"id":"order-1","amount":5000,"price":"cheap","currency":"GBP",
+		"paid":"yes","tags":["a","","c"],"items":[{"qty":0},{}]}`)
+	suite.Require().NoError(err)
+
+	err = suite.schema.Validate(kv)
+	suite.Require().Error(err)
+
+	var validationErr *ValidationError
+	suite.Require().True(errors.As(err, &validationErr))
+
+	paths := make([]string, len(validationErr.Errors))
+	for i, fieldErr := range validationErr.Errors {
+		paths[i] = fieldErr.Path
+	}
+	suite.Require().ElementsMatch([]string{
+		"amount",
+		"currency",
+		"id",
+		"items.0.qty",
+		"items.1.qty",
+		"paid",
+		"price",
+		"tags",
+		"tags.1",
+	}, paths)
+
+	// the missing keys are reported as required
+	kv = KeyValue{}
+	err = suite.schema.Validate(kv)
+	suite.Require().True(errors.As(err, &validationErr))
+	suite.Require().Len(validationErr.Errors, 3)
+	suite.Require().Equal(FieldError{Path: "id", Message: "required"}, validationErr.Errors[0])
+
+	// only the nested schema validates the kv-value
+	suite.Require().Error((&Schema{Kind: KindString}).Validate(kv))
+	suite.Require().NoError((&Schema{}).Validate(kv))
+}
+
+func (suite *TestSchemaSuite) TestFromJSON() {
+	schema, err := NewSchemaFromJSON([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "order",
+		"type": "object",
+		"required": ["id", "amount"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "string", "pattern": "^ord-[0-9]+$", "maxLength": 10},
+			"amount": {"type": "integer", "minimum": 1, "maximum": 1000},
+			"supply": {"type": "integer", "format": "big_number", "maximum": 100000000000000000000},
+			"price": {"type": "number"},
+			"paid": {"type": "boolean"},
+			"currency": {"enum": ["USD", "EUR"]},
+			"tags": {"type": "array", "items": {"type": "string", "minLength": 1}},
+			"items": {"type": "array", "minItems": 1, "items": {"type": "object", "required": ["qty"]}}
+		}
+	}`))
+	suite.Require().NoError(err)
+	suite.Require().Equal(KindNested, schema.Kind)
+	suite.Require().True(schema.DisallowAdditional)
+	suite.Require().Equal(KindBigNumber, schema.Properties["supply"].Kind)
+	suite.Require().Equal(KindStrings, schema.Properties["tags"].Kind)
+	suite.Require().Equal(KindNestedList, schema.Properties["items"].Kind)
+	suite.Require().Equal(KindAny, schema.Properties["currency"].Kind)
+
+	kv, err := NewFromString(`{"id":"ord-1","amount":5,"supply":99,"currency":"USD","tags":["a"],"items":[{"qty":1}]}`)
+	suite.Require().NoError(err)
+	suite.Require().NoError(schema.Validate(kv))
+
+	kv, err = NewFromString(`{"id":"ord-12345678","amount":0,"supply":100000000000000000001,"tags":[""],"items":[{}],"other":1}`)
+	suite.Require().NoError(err)
+	var validationErr *ValidationError
+	suite.Require().True(errors.As(schema.Validate(kv), &validationErr))
+	suite.Require().Len(validationErr.Errors, 6)
+
+	// unsupported schemas are rejected
+	_, err = NewSchemaFromJSON([]byte(`{"type": "object", "properties": {"id": {"oneOf": []}}}`))
+	suite.Require().Error(err)
+	_, err = NewSchemaFromJSON([]byte(`{"type": "array", "items": {"type": "number"}}`))
+	suite.Require().Error(err)
+	_, err = NewSchemaFromJSON([]byte(`{"type": "null"}`))
+	suite.Require().Error(err)
+	_, err = NewSchemaFromJSON([]byte(`{"type": "string", "pattern": "("}`))
+	suite.Require().Error(err)
+	_, err = NewSchemaFromJSON([]byte(`{"type": "array", "items": {"type": "string", "pattern": "("}}`))
+	suite.Require().Error(err)
+	_, err = NewSchemaFromJSON([]byte(`not a json`))
+	suite.Require().Error(err)
+}
+
+func (suite *TestSchemaSuite) TestInteger() {
+	schema, err := NewSchemaFromJSON([]byte(`{"type": "object", "properties": {"n": {"type": "integer", "maximum": 10}}}`))
+	suite.Require().NoError(err)
+	suite.Require().Equal(KindInt64, schema.Properties["n"].Kind)
+
+	for _, valid := range []string{`-1`, `0`, `10`, `5.0`} {
+		kv, err := NewFromString(`{"n":` + valid + `}`)
+		suite.Require().NoError(err)
+		suite.Require().NoError(schema.Validate(kv), valid)
+	}
+	for _, invalid := range []string{`1.5`, `"5"`, `11`, `true`} {
+		kv, err := NewFromString(`{"n":` + invalid + `}`)
+		suite.Require().NoError(err)
+		suite.Require().Error(schema.Validate(kv), invalid)
+	}
+}
+
+func (suite *TestSchemaSuite) TestPattern() {
+	// the pattern of the schema declared in go is compiled once
+	schema := &Schema{Properties: map[string]*Schema{"id": {Kind: KindString, Pattern: "^ord-[0-9]+$"}}}
+	suite.Require().NoError(schema.Validate(KeyValue{"id": "ord-1"}))
+	suite.Require().Error(schema.Validate(KeyValue{"id": "1"}))
+	_, ok := patterns.Load("^ord-[0-9]+$")
+	suite.Require().True(ok)
+
+	invalid := &Schema{Properties: map[string]*Schema{"id": {Kind: KindString, Pattern: "("}}}
+	suite.Require().Error(invalid.Validate(KeyValue{"id": "1"}))
+
+	// the loaded schema doesn't compile the pattern on the validation
+	loaded, err := NewSchemaFromJSON([]byte(`{"type": "object", "properties": {"id": {"type": "string", "pattern": "^a$"}}}`))
+	suite.Require().NoError(err)
+	suite.Require().NotNil(loaded.Properties["id"].pattern)
+	allocs := testing.AllocsPerRun(10, func() {
+		_, _ = loaded.Properties["id"].compiledPattern()
+	})
+	suite.Require().Zero(allocs)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestSchema(t *testing.T) {
+	suite.Run(t, new(TestSchemaSuite))
+}
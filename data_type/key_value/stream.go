@@ -0,0 +1,319 @@
+package key_value
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The default limits of the Decoder
+const (
+	// DefaultMaxBytes is the maximum size of one KeyValue in the stream
+	DefaultMaxBytes = 16 << 20
+	// DefaultMaxDepth is the maximum nesting of the maps and lists in one KeyValue
+	DefaultMaxDepth = 1000
+)
+
+// The errors returned by the Decoder when the KeyValue exceeds the limits
+var (
+	ErrTooLarge = errors.New("too large")
+	ErrTooDeep  = errors.New("too deep")
+)
+
+// Decoder reads the KeyValues from the stream one at a time.
+// The stream is either the newline-delimited json (NDJSON),
+// where each line is the json object, or the json array of the objects.
+// The format is detected by the first character.
+//
+//	decoder := key_value.NewDecoder(file)
+//	for {
+//		kv, err := decoder.Decode()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+//
+// Only the current KeyValue is kept in memory, and it can not exceed the limits.
+type Decoder struct {
+	reader  *bufio.Reader
+	limiter *limitReader
+	decoder *json.Decoder
+	options DecodeOptions
+
+	started bool // The format is detected
+	array   bool // The stream is the json array
+	done    bool // The stream has no more values
+}
+
+// limitReader stops reading at the limit,
+// so the json decoder never buffers more than the limit
+type limitReader struct {
+	reader   io.Reader
+	consumed int64
+	limit    int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	allowed := l.limit - l.consumed
+	if allowed <= 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > allowed {
+		p = p[:allowed]
+	}
+
+	n, err := l.reader.Read(p)
+	l.consumed += int64(n)
+	return n, err
+}
+
+// NewDecoder returns the decoder with DefaultMaxBytes and DefaultMaxDepth limits.
+// Use SetOptions to limit the KeyValues from the untrusted sources further.
+func NewDecoder(r io.Reader) *Decoder {
+	reader := bufio.NewReader(r)
+	limiter := &limitReader{reader: reader}
+	decoder := json.NewDecoder(limiter)
+	decoder.UseNumber()
+
+	return &Decoder{
+		reader:  reader,
+		limiter: limiter,
+		decoder: decoder,
+		options: DecodeOptions{MaxBytes: DefaultMaxBytes, MaxDepth: DefaultMaxDepth},
+	}
+}
+
+// SetMaxBytes sets the maximum size of one KeyValue
+func (d *Decoder) SetMaxBytes(maxBytes int64) {
+	d.options.MaxBytes = maxBytes
+}
+
+// SetMaxDepth sets the maximum nesting of one KeyValue
+func (d *Decoder) SetMaxDepth(maxDepth int) {
+	d.options.MaxDepth = maxDepth
+}
+
+// SetOptions sets all limits of one KeyValue, replacing the default ones
+func (d *Decoder) SetOptions(options DecodeOptions) {
+	d.options = options
+}
+
+// Decode returns the next KeyValue.
+// Returns io.EOF when the stream has no more KeyValues.
+// After any other error, the stream can not be decoded further.
+func (d *Decoder) Decode() (KeyValue, error) {
+	// the separator before the value is read within the limit too
+	d.setLimit(0)
+
+	if !d.started {
+		if err := d.start(); err != nil {
+			return nil, err
+		}
+	}
+	if d.done {
+		return nil, io.EOF
+	}
+
+	// the size is counted from the start of the current value, without the separator
+	more := d.decoder.More()
+	d.setLimit(d.separatorLen())
+
+	if d.array && !more {
+		if err := d.end(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := d.decoder.Decode(&raw); err != nil {
+		if err == io.EOF && !d.array {
+			d.done = true
+			return nil, io.EOF
+		}
+		return nil, d.decodeError(err)
+	}
+
+	return d.keyValue(raw)
+}
+
+// setLimit allows reading MaxBytes after the skipped bytes from the current position
+func (d *Decoder) setLimit(skipped int64) {
+	d.limiter.limit = math.MaxInt64
+	if d.options.MaxBytes > 0 {
+		d.limiter.limit = d.decoder.InputOffset() + skipped + d.options.MaxBytes
+	}
+}
+
+// separatorLen returns the amount of the buffered whitespaces and the array comma before the next value
+func (d *Decoder) separatorLen() int64 {
+	reader, ok := d.decoder.Buffered().(io.ByteReader)
+	if !ok {
+		return 0
+	}
+
+	length := int64(0)
+	comma := d.array
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return length
+		}
+		if b == ',' && comma {
+			comma = false
+		} else if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return length
+		}
+		length++
+	}
+}
+
+// start detects the format by the first character
+func (d *Decoder) start() error {
+	d.started = true
+
+	for {
+		b, err := d.reader.ReadByte()
+		if err == io.EOF {
+			d.done = true
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		if b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+			continue
+		}
+		if err := d.reader.UnreadByte(); err != nil {
+			return fmt.Errorf("unread: %w", err)
+		}
+
+		if b == '[' {
+			d.array = true
+			if _, err := d.decoder.Token(); err != nil {
+				return d.decodeError(err)
+			}
+		}
+		return nil
+	}
+}
+
+// end reads the end of the json array. Nothing is allowed after it.
+func (d *Decoder) end() error {
+	d.done = true
+
+	if _, err := d.decoder.Token(); err != nil {
+		return d.decodeError(err)
+	}
+	if _, err := d.decoder.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after the array")
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeError(err error) error {
+	if errors.Is(err, ErrTooLarge) {
+		return fmt.Errorf("key value exceeds %d bytes: %w", d.options.MaxBytes, ErrTooLarge)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("json.decoder: %w", err)
+}
+
+// keyValue checks the limits and decodes the raw json object
+func (d *Decoder) keyValue(raw json.RawMessage) (KeyValue, error) {
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, fmt.Errorf("value at %d is not a json object", d.decoder.InputOffset()-int64(len(raw)))
+	}
+	if err := d.options.check(raw); err != nil {
+		return nil, fmt.Errorf("options: %w", err)
+	}
+
+	var kv KeyValue
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&kv); err != nil {
+		return nil, fmt.Errorf("json.decoder: %w", err)
+	}
+	if err := kv.noNilValue(); err != nil {
+		return nil, fmt.Errorf("value is nil: %w", err)
+	}
+
+	return kv, nil
+}
+
+// Encoder writes the KeyValues into the stream one at a time.
+// Each KeyValue is written in the Canonical form.
+//
+// The encoder returned by NewEncoder writes the newline-delimited json (NDJSON).
+// The encoder returned by NewArrayEncoder writes the json array,
+// that is closed by Close.
+type Encoder struct {
+	writer io.Writer
+	array  bool
+	count  int
+}
+
+// NewEncoder returns the encoder of the newline-delimited json
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{writer: w}
+}
+
+// NewArrayEncoder returns the encoder of the json array.
+// Call Close to write the end of the array.
+func NewArrayEncoder(w io.Writer) *Encoder {
+	return &Encoder{writer: w, array: true}
+}
+
+// Encode writes the KeyValue
+func (e *Encoder) Encode(kv KeyValue) error {
+	encoded, err := kv.Canonical()
+	if err != nil {
+		return fmt.Errorf("kv.Canonical: %w", err)
+	}
+
+	var prefix string
+	if e.array {
+		prefix = ","
+		if e.count == 0 {
+			prefix = "["
+		}
+	}
+	e.count++
+
+	buffer := make([]byte, 0, len(prefix)+len(encoded)+1)
+	buffer = append(buffer, prefix...)
+	buffer = append(buffer, encoded...)
+	buffer = append(buffer, '\n')
+	if _, err := e.writer.Write(buffer); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// Close writes the end of the json array.
+// The encoder of the newline-delimited json has nothing to write.
+// The writer is not closed.
+func (e *Encoder) Close() error {
+	if !e.array {
+		return nil
+	}
+
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(e.writer, end); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
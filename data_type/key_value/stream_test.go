@@ -0,0 +1,198 @@
+package key_value
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestStreamSuite struct {
+	suite.Suite
+}
+
+// decodeAll is the helper that decodes the stream until the end or the error
+func (suite *TestStreamSuite) decodeAll(decoder *Decoder) ([]KeyValue, error) {
+	list := make([]KeyValue, 0)
+	for {
+		kv, err := decoder.Decode()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return list, err
+		}
+		list = append(list, kv)
+	}
+}
+
+func (suite *TestStreamSuite) TestNDJSON() {
+	stream := `{"id":1,"supply":123456789012345678901234567890}
+{"id":2,"tags":["a"]}
+
+  {"id":3,"nested":{"qty":5}}
+`
+	list, err := suite.decodeAll(NewDecoder(strings.NewReader(stream)))
+	suite.Require().NoError(err)
+	suite.Require().Len(list, 3)
+
+	for i, kv := range list {
+		id, err := kv.Uint64Value("id")
+		suite.Require().NoError(err)
+		suite.Require().Equal(uint64(i+1), id)
+	}
+
+	supply, err := list[0].BigIntValue("supply")
+	suite.Require().NoError(err)
+	suite.Require().Equal("123456789012345678901234567890", supply.String())
+
+	// the empty stream
+	list, err = suite.decodeAll(NewDecoder(strings.NewReader(" \n")))
+	suite.Require().NoError(err)
+	suite.Require().Empty(list)
+}
+
+func (suite *TestStreamSuite) TestArray() {
+	list, err := suite.decodeAll(NewDecoder(strings.NewReader(`
+		[{"id":1}, {"id":2}]
+	`)))
+	suite.Require().NoError(err)
+	suite.Require().Len(list, 2)
+
+	list, err = suite.decodeAll(NewDecoder(strings.NewReader(`[]`)))
+	suite.Require().NoError(err)
+	suite.Require().Empty(list)
+
+	// not closed
+	_, err = suite.decodeAll(NewDecoder(strings.NewReader(`[{"id":1}`)))
+	suite.Require().Error(err)
+
+	// data after the array
+	list, err = suite.decodeAll(NewDecoder(strings.NewReader(`[{"id":1}] {"id":2}`)))
+	suite.Require().Error(err)
+	suite.Require().Len(list, 1)
+}
+
+func (suite *TestStreamSuite) TestInvalid() {
+	// not an object
+	list, err := suite.decodeAll(NewDecoder(strings.NewReader("{\"id\":1}\n5\n")))
+	suite.Require().Error(err)
+	suite.Require().Len(list, 1)
+
+	_, err = suite.decodeAll(NewDecoder(strings.NewReader(`[{"id":1}, "a"]`)))
+	suite.Require().Error(err)
+
+	_, err = suite.decodeAll(NewDecoder(strings.NewReader(`{"id":null}`)))
+	suite.Require().ErrorIs(err, ErrNilValue)
+
+	_, err = suite.decodeAll(NewDecoder(strings.NewReader(`{"id":`)))
+	suite.Require().Error(err)
+}
+
+func (suite *TestStreamSuite) TestLimits() {
+	small := `{"id":1}`
+	large := `{"id":"` + strings.Repeat("a", 100) + `"}`
+
+	// each value is limited, not the whole stream
+	decoder := NewDecoder(strings.NewReader(strings.Repeat(small+"\n", 100)))
+	decoder.SetMaxBytes(int64(len(small) + 1))
+	list, err := suite.decodeAll(decoder)
+	suite.Require().NoError(err)
+	suite.Require().Len(list, 100)
+
+	decoder = NewDecoder(strings.NewReader(small + "\n" + large + "\n"))
+	decoder.SetMaxBytes(50)
+	list, err = suite.decodeAll(decoder)
+	suite.Require().ErrorIs(err, ErrTooLarge)
+	suite.Require().Len(list, 1)
+
+	decoder = NewDecoder(strings.NewReader("[" + small + "," + large + "]"))
+	decoder.SetMaxBytes(50)
+	_, err = suite.decodeAll(decoder)
+	suite.Require().ErrorIs(err, ErrTooLarge)
+
+	// the separators are not counted in the size of the value
+	exact := `{"a":"12345678"}`
+	decoder = NewDecoder(strings.NewReader(exact + "\n\n" + exact + "\r\n" + exact))
+	decoder.SetMaxBytes(int64(len(exact)))
+	list, err = suite.decodeAll(decoder)
+	suite.Require().NoError(err)
+	suite.Require().Len(list, 3)
+
+	decoder = NewDecoder(strings.NewReader("[" + exact + ", " + exact + "\n]"))
+	decoder.SetMaxBytes(int64(len(exact)))
+	list, err = suite.decodeAll(decoder)
+	suite.Require().NoError(err)
+	suite.Require().Len(list, 2)
+
+	decoder = NewDecoder(strings.NewReader(exact + "\n" + exact + "\n"))
+	decoder.SetMaxBytes(int64(len(exact) - 1))
+	list, err = suite.decodeAll(decoder)
+	suite.Require().ErrorIs(err, ErrTooLarge)
+	suite.Require().Empty(list)
+
+	deep := `{"a":{"b":[{"c":1}]}}`
+	decoder = NewDecoder(strings.NewReader(deep))
+	decoder.SetMaxDepth(3)
+	_, err = suite.decodeAll(decoder)
+	suite.Require().ErrorIs(err, ErrTooDeep)
+
+	decoder = NewDecoder(strings.NewReader(deep))
+	decoder.SetMaxDepth(4)
+	list, err = suite.decodeAll(decoder)
+	suite.Require().NoError(err)
+	suite.Require().Len(list, 1)
+
+	// the brackets in the strings are not counted
+	decoder = NewDecoder(strings.NewReader(`{"a":"[[[\"{{{"}`))
+	decoder.SetMaxDepth(1)
+	_, err = suite.decodeAll(decoder)
+	suite.Require().NoError(err)
+}
+
+func (suite *TestStreamSuite) TestEncoder() {
+	list := []KeyValue{{"id": 1, "name": "<a>"}, {"id": uint64(2)}}
+
+	buffer := bytes.Buffer{}
+	encoder := NewEncoder(&buffer)
+	for _, kv := range list {
+		suite.Require().NoError(encoder.Encode(kv))
+	}
+	suite.Require().NoError(encoder.Close())
+	suite.Require().Equal("{\"id\":1,\"name\":\"<a>\"}\n{\"id\":2}\n", buffer.String())
+
+	decoded, err := suite.decodeAll(NewDecoder(&buffer))
+	suite.Require().NoError(err)
+	suite.Require().Len(decoded, 2)
+	suite.Require().True(list[0].Equal(decoded[0]))
+
+	buffer.Reset()
+	encoder = NewArrayEncoder(&buffer)
+	for _, kv := range list {
+		suite.Require().NoError(encoder.Encode(kv))
+	}
+	suite.Require().NoError(encoder.Close())
+
+	decoded, err = suite.decodeAll(NewDecoder(&buffer))
+	suite.Require().NoError(err)
+	suite.Require().Len(decoded, 2)
+	suite.Require().True(list[1].Equal(decoded[1]))
+
+	buffer.Reset()
+	encoder = NewArrayEncoder(&buffer)
+	suite.Require().NoError(encoder.Close())
+	suite.Require().Equal("[]\n", buffer.String())
+
+	suite.Require().ErrorIs(encoder.Encode(KeyValue{"nil": nil}), ErrNilValue)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestStream(t *testing.T) {
+	suite.Run(t, new(TestStreamSuite))
+}
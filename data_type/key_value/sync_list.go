@@ -0,0 +1,253 @@
+package key_value
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// SyncList is the List that could be shared between the goroutines.
+// Besides the List methods, it has the atomic operations
+// that read and change the element under one lock.
+type SyncList struct {
+	mu   sync.RWMutex
+	list *List
+}
+
+// NewSyncList returns a new list of the elements that could contain
+// maximum DefaultCap of elements.
+func NewSyncList() *SyncList {
+	return &SyncList{
+		list: NewList(),
+	}
+}
+
+func (s *SyncList) Len() uint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Len()
+}
+
+func (s *SyncList) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.IsEmpty()
+}
+
+func (s *SyncList) IsFull() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.IsFull()
+}
+
+// List returns the copy of the elements as a map.
+func (s *SyncList) List() map[interface{}]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.List()
+}
+
+// SetCap updates the capacity of the list.
+// If the list has more elements than newCap, it throws an error.
+func (s *SyncList) SetCap(newCap uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list.SetCap(newCap)
+}
+
+// Cap returns the capacity of the list
+func (s *SyncList) Cap() uint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Cap()
+}
+
+// Add a new element to the end of the list.
+func (s *SyncList) Add(key interface{}, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list.Add(key, value)
+}
+
+func (s *SyncList) Exist(key interface{}) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Exist(key)
+}
+
+// Get the element in the list
+func (s *SyncList) Get(key interface{}) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Get(key)
+}
+
+// GetFirst returns the first added element.
+func (s *SyncList) GetFirst() (interface{}, interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.GetFirst()
+}
+
+// GetLast returns the last added element.
+func (s *SyncList) GetLast() (interface{}, interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.GetLast()
+}
+
+// Take is a Get, but removes the returned element from the list
+func (s *SyncList) Take(key interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list.Take(key)
+}
+
+// TakeFirst is a GetFirst, but removes the element from the list
+func (s *SyncList) TakeFirst() (interface{}, interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list.TakeFirst()
+}
+
+// MoveToBack makes the element the last one as if it was added just now.
+func (s *SyncList) MoveToBack(key interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list.MoveToBack(key)
+}
+
+// Keys returns the keys in the order of addition
+func (s *SyncList) Keys() []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Keys()
+}
+
+// Values returns the values in the order of addition
+func (s *SyncList) Values() []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Values()
+}
+
+// Range calls f for each element in the order of addition.
+// If f returns false, then the iteration stops.
+//
+// The list is locked for reading during the iteration,
+// therefore, f must not call the methods of this list.
+func (s *SyncList) Range(f func(key interface{}, value interface{}) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.list.Range(f)
+}
+
+// GetOrAdd returns the existing value of the key.
+// Otherwise, it adds the value and returns it.
+// The loaded is true if the value was in the list.
+func (s *SyncList) GetOrAdd(key interface{}, value interface{}) (actual interface{}, loaded bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.list.Exist(key) {
+		actual, err = s.list.Get(key)
+		if err != nil {
+			return nil, false, fmt.Errorf("list.Get: %w", err)
+		}
+		return actual, true, nil
+	}
+
+	if err := s.list.Add(key, value); err != nil {
+		return nil, false, fmt.Errorf("list.Add: %w", err)
+	}
+
+	return value, false, nil
+}
+
+// CompareAndSwap replaces the value of the key with newValue,
+// only if the current value is equal to the old.
+// The values are compared by reflect.DeepEqual.
+// Returns true if the value was replaced.
+func (s *SyncList) CompareAndSwap(key interface{}, old interface{}, newValue interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.list.Get(key)
+	if err != nil {
+		return false, fmt.Errorf("list.Get: %w", err)
+	}
+	if !reflect.DeepEqual(current, old) {
+		return false, nil
+	}
+	if err := s.list.validate(key, newValue); err != nil {
+		return false, fmt.Errorf("list.validate: %w", err)
+	}
+
+	s.list.replace(key, newValue)
+
+	return true, nil
+}
+
+// Update replaces the value of the key with the value returned by f.
+// The f receives the current value.
+// If f returns an error, then the value is not changed.
+//
+// The list is locked during f, therefore, f must not call the methods of this list.
+func (s *SyncList) Update(key interface{}, f func(value interface{}) (interface{}, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.list.Get(key)
+	if err != nil {
+		return fmt.Errorf("list.Get: %w", err)
+	}
+	newValue, err := f(current)
+	if err != nil {
+		return fmt.Errorf("f: %w", err)
+	}
+	if err := s.list.validate(key, newValue); err != nil {
+		return fmt.Errorf("list.validate: %w", err)
+	}
+
+	s.list.replace(key, newValue)
+
+	return nil
+}
+
+// TakeIf removes the element and returns its value only if the predicate returns true.
+// The taken is false if the element was kept in the list.
+//
+// The list is locked during predicate, therefore, predicate must not call the methods of this list.
+func (s *SyncList) TakeIf(key interface{}, predicate func(value interface{}) bool) (value interface{}, taken bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, err = s.list.Get(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("list.Get: %w", err)
+	}
+	if !predicate(value) {
+		return value, false, nil
+	}
+
+	s.list.remove(key)
+
+	return value, true, nil
+}
@@ -0,0 +1,138 @@
+package key_value
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestSyncListSuite struct {
+	suite.Suite
+	list *SyncList
+}
+
+// SetupTest prepares the empty list
+func (suite *TestSyncListSuite) SetupTest() {
+	list := NewSyncList()
+	suite.list = list
+
+	suite.Require().True(list.IsEmpty())
+	suite.Require().False(list.IsFull())
+	suite.Require().Equal(DefaultCap, list.Cap())
+}
+
+func (suite *TestSyncListSuite) TestGetOrAdd() {
+	actual, loaded, err := suite.list.GetOrAdd("a", uint64(1))
+	suite.Require().NoError(err)
+	suite.Require().False(loaded)
+	suite.Require().Equal(uint64(1), actual)
+
+	actual, loaded, err = suite.list.GetOrAdd("a", uint64(2))
+	suite.Require().NoError(err)
+	suite.Require().True(loaded)
+	suite.Require().Equal(uint64(1), actual)
+
+	// the list rules are applied
+	_, _, err = suite.list.GetOrAdd("b", "2")
+	suite.Require().Error(err)
+}
+
+func (suite *TestSyncListSuite) TestCompareAndSwap() {
+	suite.Require().NoError(suite.list.Add("a", uint64(1)))
+	suite.Require().NoError(suite.list.Add("b", uint64(2)))
+
+	swapped, err := suite.list.CompareAndSwap("a", uint64(5), uint64(10))
+	suite.Require().NoError(err)
+	suite.Require().False(swapped)
+
+	swapped, err = suite.list.CompareAndSwap("a", uint64(1), uint64(10))
+	suite.Require().NoError(err)
+	suite.Require().True(swapped)
+
+	value, err := suite.list.Get("a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(10), value)
+
+	// the position is kept
+	suite.Require().Equal([]interface{}{"a", "b"}, suite.list.Keys())
+
+	_, err = suite.list.CompareAndSwap("a", uint64(10), "invalid type")
+	suite.Require().Error(err)
+	_, err = suite.list.CompareAndSwap("c", uint64(10), uint64(11))
+	suite.Require().Error(err)
+}
+
+func (suite *TestSyncListSuite) TestUpdateTakeIf() {
+	suite.Require().NoError(suite.list.Add("a", uint64(1)))
+
+	err := suite.list.Update("a", func(value interface{}) (interface{}, error) {
+		return value.(uint64) + 1, nil
+	})
+	suite.Require().NoError(err)
+
+	err = suite.list.Update("a", func(value interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("no update")
+	})
+	suite.Require().Error(err)
+
+	value, taken, err := suite.list.TakeIf("a", func(value interface{}) bool {
+		return value.(uint64) > 2
+	})
+	suite.Require().NoError(err)
+	suite.Require().False(taken)
+	suite.Require().Equal(uint64(2), value)
+
+	value, taken, err = suite.list.TakeIf("a", func(value interface{}) bool {
+		return value.(uint64) == 2
+	})
+	suite.Require().NoError(err)
+	suite.Require().True(taken)
+	suite.Require().Equal(uint64(2), value)
+	suite.Require().False(suite.list.Exist("a"))
+
+	_, _, err = suite.list.TakeIf("a", func(interface{}) bool { return true })
+	suite.Require().Error(err)
+}
+
+func (suite *TestSyncListSuite) TestConcurrentUpdate() {
+	suite.Require().NoError(suite.list.Add("counter", uint64(0)))
+
+	workers := 8
+	increments := 100
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				err := suite.list.Update("counter", func(value interface{}) (interface{}, error) {
+					return value.(uint64) + 1, nil
+				})
+				suite.NoError(err)
+
+				_, _, err = suite.list.GetOrAdd(fmt.Sprintf("worker_%d", w), uint64(i))
+				suite.NoError(err)
+				suite.list.Keys()
+			}
+			_, _, err := suite.list.TakeIf(fmt.Sprintf("worker_%d", w), func(interface{}) bool { return true })
+			suite.NoError(err)
+		}(w)
+	}
+	wg.Wait()
+
+	value, err := suite.list.Get("counter")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(workers*increments), value)
+	suite.Require().EqualValues(1, suite.list.Len())
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestSyncList(t *testing.T) {
+	suite.Run(t, new(TestSyncListSuite))
+}
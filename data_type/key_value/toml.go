@@ -0,0 +1,176 @@
+package key_value
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// The formats of the TOML local date and time values,
+// matched by the name of the location that the toml decoder sets
+var tomlLocalFormats = map[string]string{
+	"datetime-local": "2006-01-02T15:04:05.999999999",
+	"date-local":     "2006-01-02",
+	"time-local":     "15:04:05.999999999",
+}
+
+// NewFromTOML decodes the TOML document into KeyValue.
+//
+// The values get the same types as in NewFromYAML:
+// the natural numbers and zero are uint64, the negative integers are int64, and the floats are float64.
+// The tables and the arrays of tables are the nested maps and lists.
+// The dates and times are kept as the strings:
+// the offset date-times in RFC 3339, the local ones in the TOML format.
+//
+// TOML has no null, and its integers are limited to 64-bit signed numbers.
+func NewFromTOML(data []byte) (KeyValue, error) {
+	decoded := make(map[string]interface{})
+	if err := toml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("toml.Unmarshal: %w", err)
+	}
+
+	converted, err := tomlMap(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("toml: %w", err)
+	}
+
+	return converted, nil
+}
+
+// TOML serializes k into the TOML document.
+// The nested maps are written as the tables, and the lists of maps as the arrays of tables.
+// The keys are sorted.
+//
+// The []byte is written as the base64 string, same as json does.
+// TOML can't keep the integers beyond int64,
+// so the big numbers and the uint64 greater than math.MaxInt64 return an error.
+func (k KeyValue) TOML() ([]byte, error) {
+	value, err := binaryMap(k)
+	if err != nil {
+		return nil, fmt.Errorf("binaryMap: %w", err)
+	}
+	converted, err := tomlEncodable(value)
+	if err != nil {
+		return nil, fmt.Errorf("toml: %w", err)
+	}
+
+	buffer := bytes.Buffer{}
+	encoder := toml.NewEncoder(&buffer)
+	encoder.Indent = ""
+	if err := encoder.Encode(converted); err != nil {
+		return nil, fmt.Errorf("toml.Encode: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func tomlMap(decoded map[string]interface{}) (map[string]interface{}, error) {
+	converted := make(map[string]interface{}, len(decoded))
+	for key, value := range decoded {
+		convertedValue, err := tomlValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		converted[key] = convertedValue
+	}
+
+	return converted, nil
+}
+
+// tomlValue converts the value returned by the toml decoder
+func tomlValue(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case bool, string, float64:
+		return typed, nil
+	case int64:
+		if typed >= 0 {
+			return uint64(typed), nil
+		}
+		return typed, nil
+	case time.Time:
+		if format, ok := tomlLocalFormats[typed.Location().String()]; ok {
+			return typed.Format(format), nil
+		}
+		return typed.Format(time.RFC3339Nano), nil
+	case map[string]interface{}:
+		return tomlMap(typed)
+	case []map[string]interface{}:
+		list := make([]interface{}, len(typed))
+		for i, element := range typed {
+			converted, err := tomlMap(element)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case []interface{}:
+		list := make([]interface{}, len(typed))
+		for i, element := range typed {
+			converted, err := tomlValue(element)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			list[i] = converted
+		}
+		return list, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %T", value)
+}
+
+// tomlEncodable returns the value converted by binaryValue in the types that the toml encoder writes as expected
+func tomlEncodable(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case bool, string, int64, float64:
+		return typed, nil
+	case uint64:
+		if typed > math.MaxInt64 {
+			return nil, fmt.Errorf("%d overflows the TOML integer", typed)
+		}
+		return int64(typed), nil
+	case *big.Int:
+		if !typed.IsInt64() {
+			return nil, fmt.Errorf("%s overflows the TOML integer", typed)
+		}
+		return typed.Int64(), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(typed), nil
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, element := range typed {
+			convertedElement, err := tomlEncodable(element)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			converted[key] = convertedElement
+		}
+		return converted, nil
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		tables := make([]map[string]interface{}, 0, len(typed))
+		for i, element := range typed {
+			convertedElement, err := tomlEncodable(element)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			converted[i] = convertedElement
+			if table, ok := convertedElement.(map[string]interface{}); ok {
+				tables = append(tables, table)
+			}
+		}
+		// the list of maps is written as the array of tables
+		if len(typed) > 0 && len(tables) == len(typed) {
+			return tables, nil
+		}
+		return converted, nil
+	}
+
+	// binaryValue returns only the types above
+	panic(fmt.Sprintf("unsupported type %T", value))
+}
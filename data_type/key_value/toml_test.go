@@ -0,0 +1,125 @@
+package key_value
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestTOMLSuite struct {
+	suite.Suite
+}
+
+func (suite *TestTOMLSuite) TestNewFromTOML() {
+	kv, err := NewFromTOML([]byte(`
+name = "sds"
+amount = 5
+delta = -5
+price = 1.5
+paid = true
+max = 9223372036854775807
+created = 2023-01-02T03:04:05Z
+day = 2023-01-02
+tags = ["a", "b"]
+
+[order]
+id = 1
+
+[[order.items]]
+qty = 2
+
+[[order.items]]
+qty = 3
+`))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal("sds", kv["name"])
+	suite.Require().Equal(uint64(5), kv["amount"])
+	suite.Require().Equal(int64(-5), kv["delta"])
+	suite.Require().Equal(1.5, kv["price"])
+	suite.Require().Equal(true, kv["paid"])
+	suite.Require().Equal(uint64(math.MaxInt64), kv["max"])
+	suite.Require().Equal("2023-01-02T03:04:05Z", kv["created"])
+	suite.Require().Equal("2023-01-02", kv["day"])
+
+	tags, err := kv.StringsValue("tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a", "b"}, tags)
+
+	items, err := kv.NestedListAt("order.items")
+	suite.Require().NoError(err)
+	suite.Require().Len(items, 2)
+	qty, err := items[1].Uint64Value("qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(3), qty)
+
+	_, err = NewFromTOML([]byte("name = "))
+	suite.Require().Error(err)
+	// out of the TOML integer range
+	_, err = NewFromTOML([]byte("max = 9223372036854775808"))
+	suite.Require().Error(err)
+}
+
+func (suite *TestTOMLSuite) TestRoundTrip() {
+	kv := KeyValue{
+		"int":     int64(-5),
+		"uint":    uint64(5),
+		"float":   float64(5),
+		"big":     big.NewInt(7),
+		"bool":    true,
+		"bytes":   []byte{1, 2},
+		"strings": []string{"a"},
+		"empty":   []interface{}{},
+		"nested":  KeyValue{"id": 1, "items": []KeyValue{{"qty": 1}, {"qty": 2}}},
+	}
+
+	encoded, err := kv.TOML()
+	suite.Require().NoError(err)
+	suite.Require().Contains(string(encoded), "[[nested.items]]")
+
+	decoded, err := NewFromTOML(encoded)
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(int64(-5), decoded["int"])
+	suite.Require().Equal(uint64(5), decoded["uint"])
+	suite.Require().Equal(float64(5), decoded["float"])
+	suite.Require().Equal(uint64(7), decoded["big"])
+	suite.Require().Equal([]interface{}{"a"}, decoded["strings"])
+
+	b, err := decoded.BytesValue("bytes")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte{1, 2}, b)
+
+	qty, err := decoded.Uint64At("nested.items.1.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(2), qty)
+
+	// the same kv gives the same document
+	again, err := kv.TOML()
+	suite.Require().NoError(err)
+	suite.Require().Equal(encoded, again)
+}
+
+func (suite *TestTOMLSuite) TestOverflow() {
+	_, err := KeyValue{"uint": uint64(math.MaxUint64)}.TOML()
+	suite.Require().Error(err)
+
+	kv, err := NewFromString(`{"supply":123456789012345678901234567890}`)
+	suite.Require().NoError(err)
+	_, err = kv.TOML()
+	suite.Require().Error(err)
+
+	_, err = KeyValue{"nil": nil}.TOML()
+	suite.Require().ErrorIs(err, ErrNilValue)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTOML(t *testing.T) {
+	suite.Run(t, new(TestTOMLSuite))
+}
@@ -0,0 +1,263 @@
+package key_value
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/ahmetson/datatype-lib/data_type"
+)
+
+// typedEntry is the element of the TypedList kept in the order of addition
+type typedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// TypedList is the generic version of the List.
+// The key and value types are checked by the compiler,
+// therefore, the list doesn't need to compare the types at runtime.
+type TypedList[K comparable, V any] struct {
+	l     map[K]*list.Element
+	order *list.List
+	cap   uint
+}
+
+// NewTypedList returns a new list of the elements that could contain
+// maximum DefaultCap of elements.
+func NewTypedList[K comparable, V any]() *TypedList[K, V] {
+	return &TypedList[K, V]{
+		cap:   DefaultCap,
+		l:     map[K]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// NewTypedListFromList converts the List into the TypedList.
+// The order and the cap of the elements are kept.
+// Returns an error if the list has the elements of other types.
+func NewTypedListFromList[K comparable, V any](from *List) (*TypedList[K, V], error) {
+	typed := NewTypedList[K, V]()
+	typed.cap = from.Cap()
+
+	var err error
+	from.Range(func(rawKey interface{}, rawValue interface{}) bool {
+		key, ok := rawKey.(K)
+		if !ok {
+			err = fmt.Errorf("kv type %T can not be converted to %T", rawKey, key)
+			return false
+		}
+		value, ok := rawValue.(V)
+		if !ok {
+			err = fmt.Errorf("value type %T can not be converted to %T", rawValue, value)
+			return false
+		}
+		if err = typed.Add(key, value); err != nil {
+			err = fmt.Errorf("typed.Add: %w", err)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
+
+// ToList converts the TypedList into the List.
+// The order and the cap of the elements are kept.
+func (q *TypedList[K, V]) ToList() (*List, error) {
+	converted := NewList()
+	converted.cap = q.cap
+
+	var err error
+	q.Range(func(key K, value V) bool {
+		if err = converted.Add(key, value); err != nil {
+			err = fmt.Errorf("converted.Add: %w", err)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
+
+func (q *TypedList[K, V]) Len() uint {
+	return uint(len(q.l))
+}
+
+func (q *TypedList[K, V]) IsEmpty() bool {
+	return len(q.l) == 0
+}
+
+func (q *TypedList[K, V]) IsFull() bool {
+	return q.Len() == q.cap
+}
+
+// List returns the elements as a map.
+// The returned map is a copy, changing it doesn't affect the list.
+func (q *TypedList[K, V]) List() map[K]V {
+	elements := make(map[K]V, len(q.l))
+	for key, element := range q.l {
+		elements[key] = element.Value.(*typedEntry[K, V]).value
+	}
+
+	return elements
+}
+
+// SetCap updates the capacity of the list.
+// If the list has more elements than newCap, it throws an error.
+func (q *TypedList[K, V]) SetCap(newCap uint) error {
+	if q.Len() > newCap {
+		return fmt.Errorf("list has %d elements. can not set cap to %d", q.Len(), newCap)
+	}
+
+	q.cap = newCap
+
+	return nil
+}
+
+// Cap returns the capacity of the list
+func (q *TypedList[K, V]) Cap() uint {
+	return q.cap
+}
+
+// Add a new element to the end of the list.
+// Returns an error if the list is full, or the key exists.
+// Same as List, the key can not be a pointer, the value can not be nil.
+func (q *TypedList[K, V]) Add(key K, value V) error {
+	if q.IsFull() {
+		return fmt.Errorf("list is already full")
+	}
+	if data_type.IsPointer(key) {
+		return fmt.Errorf("the kv was passed by the pointer")
+	}
+	if data_type.IsNil(value) {
+		return fmt.Errorf("the value parameer is nil")
+	}
+	if _, ok := q.l[key]; ok {
+		return fmt.Errorf("the element exists")
+	}
+
+	q.l[key] = q.order.PushBack(&typedEntry[K, V]{key: key, value: value})
+
+	return nil
+}
+
+func (q *TypedList[K, V]) Exist(key K) bool {
+	_, ok := q.l[key]
+	return ok
+}
+
+// Get the element in the list
+func (q *TypedList[K, V]) Get(key K) (V, error) {
+	element, ok := q.l[key]
+	if !ok {
+		var empty V
+		return empty, fmt.Errorf("the element not found")
+	}
+
+	return element.Value.(*typedEntry[K, V]).value, nil
+}
+
+// GetFirst returns the first added element.
+// Returns the kv, value and error if it can not find it.
+func (q *TypedList[K, V]) GetFirst() (K, V, error) {
+	element := q.order.Front()
+	if element == nil {
+		var key K
+		var value V
+		return key, value, fmt.Errorf("empty list")
+	}
+
+	entry := element.Value.(*typedEntry[K, V])
+	return entry.key, entry.value, nil
+}
+
+// GetLast returns the last added element.
+// Returns the kv, value and error if it can not find it.
+func (q *TypedList[K, V]) GetLast() (K, V, error) {
+	element := q.order.Back()
+	if element == nil {
+		var key K
+		var value V
+		return key, value, fmt.Errorf("empty list")
+	}
+
+	entry := element.Value.(*typedEntry[K, V])
+	return entry.key, entry.value, nil
+}
+
+// Take is a Get, but removes the returned element from the list
+func (q *TypedList[K, V]) Take(key K) (V, error) {
+	element, ok := q.l[key]
+	if !ok {
+		var empty V
+		return empty, fmt.Errorf("the element not found")
+	}
+
+	q.order.Remove(element)
+	delete(q.l, key)
+
+	return element.Value.(*typedEntry[K, V]).value, nil
+}
+
+// TakeFirst is a GetFirst, but removes the element from the list
+func (q *TypedList[K, V]) TakeFirst() (K, V, error) {
+	key, value, err := q.GetFirst()
+	if err != nil {
+		return key, value, fmt.Errorf("list.GetFirst: %w", err)
+	}
+
+	q.order.Remove(q.l[key])
+	delete(q.l, key)
+
+	return key, value, nil
+}
+
+// MoveToBack makes the element the last one as if it was added just now.
+func (q *TypedList[K, V]) MoveToBack(key K) error {
+	element, ok := q.l[key]
+	if !ok {
+		return fmt.Errorf("the element not found")
+	}
+
+	q.order.MoveToBack(element)
+
+	return nil
+}
+
+// Keys returns the keys in the order of addition
+func (q *TypedList[K, V]) Keys() []K {
+	keys := make([]K, 0, len(q.l))
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*typedEntry[K, V]).key)
+	}
+
+	return keys
+}
+
+// Values returns the values in the order of addition
+func (q *TypedList[K, V]) Values() []V {
+	values := make([]V, 0, len(q.l))
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		values = append(values, element.Value.(*typedEntry[K, V]).value)
+	}
+
+	return values
+}
+
+// Range calls f for each element in the order of addition.
+// If f returns false, then the iteration stops.
+// The list must not be changed during the iteration.
+func (q *TypedList[K, V]) Range(f func(key K, value V) bool) {
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*typedEntry[K, V])
+		if !f(entry.key, entry.value) {
+			return
+		}
+	}
+}
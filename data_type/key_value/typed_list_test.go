@@ -0,0 +1,125 @@
+package key_value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestTypedListSuite struct {
+	suite.Suite
+	list *TypedList[string, uint64]
+}
+
+// SetupTest prepares the empty list
+func (suite *TestTypedListSuite) SetupTest() {
+	list := NewTypedList[string, uint64]()
+	suite.list = list
+
+	suite.Require().True(list.IsEmpty())
+	suite.Require().False(list.IsFull())
+	suite.Require().Zero(list.Len())
+	suite.Require().Equal(DefaultCap, list.Cap())
+}
+
+func (suite *TestTypedListSuite) TestAddGet() {
+	suite.Require().NoError(suite.list.Add("a", 1))
+	suite.Require().NoError(suite.list.Add("b", 2))
+	suite.Require().Error(suite.list.Add("a", 3))
+
+	value, err := suite.list.Get("a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(1), value)
+
+	_, err = suite.list.Get("c")
+	suite.Require().Error(err)
+	suite.Require().True(suite.list.Exist("b"))
+	suite.Require().False(suite.list.Exist("c"))
+
+	suite.Require().Equal(map[string]uint64{"a": 1, "b": 2}, suite.list.List())
+
+	value, err = suite.list.Take("a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(1), value)
+	_, err = suite.list.Take("a")
+	suite.Require().Error(err)
+
+	// the values can not be nil
+	values := NewTypedList[string, interface{}]()
+	suite.Require().Error(values.Add("nil", nil))
+	// the keys can not be pointers
+	key := "key"
+	pointerKeys := NewTypedList[*string, uint64]()
+	suite.Require().Error(pointerKeys.Add(&key, 1))
+}
+
+func (suite *TestTypedListSuite) TestCap() {
+	suite.Require().NoError(suite.list.SetCap(1))
+	suite.Require().NoError(suite.list.Add("a", 1))
+	suite.Require().True(suite.list.IsFull())
+	suite.Require().Error(suite.list.Add("b", 2))
+	suite.Require().Error(suite.list.SetCap(0))
+}
+
+func (suite *TestTypedListSuite) TestOrder() {
+	for i, key := range []string{"zeta", "alpha", "mu"} {
+		suite.Require().NoError(suite.list.Add(key, uint64(i)))
+	}
+	suite.Require().Equal([]string{"zeta", "alpha", "mu"}, suite.list.Keys())
+	suite.Require().Equal([]uint64{0, 1, 2}, suite.list.Values())
+
+	suite.Require().NoError(suite.list.MoveToBack("zeta"))
+	suite.Require().Error(suite.list.MoveToBack("none"))
+
+	key, value, err := suite.list.GetLast()
+	suite.Require().NoError(err)
+	suite.Require().Equal("zeta", key)
+	suite.Require().Equal(uint64(0), value)
+
+	key, value, err = suite.list.TakeFirst()
+	suite.Require().NoError(err)
+	suite.Require().Equal("alpha", key)
+	suite.Require().Equal(uint64(1), value)
+
+	visited := map[string]uint64{}
+	suite.list.Range(func(key string, value uint64) bool {
+		visited[key] = value
+		return true
+	})
+	suite.Require().Equal(map[string]uint64{"mu": 2, "zeta": 0}, visited)
+
+	_, _, err = NewTypedList[string, uint64]().GetFirst()
+	suite.Require().Error(err)
+}
+
+func (suite *TestTypedListSuite) TestConversion() {
+	suite.Require().NoError(suite.list.SetCap(5))
+	suite.Require().NoError(suite.list.Add("b", 2))
+	suite.Require().NoError(suite.list.Add("a", 1))
+
+	untyped, err := suite.list.ToList()
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint(5), untyped.Cap())
+	suite.Require().Equal([]interface{}{"b", "a"}, untyped.Keys())
+
+	typed, err := NewTypedListFromList[string, uint64](untyped)
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint(5), typed.Cap())
+	suite.Require().Equal([]string{"b", "a"}, typed.Keys())
+	suite.Require().Equal([]uint64{2, 1}, typed.Values())
+
+	// the types are not matching
+	_, err = NewTypedListFromList[string, int](untyped)
+	suite.Require().Error(err)
+	_, err = NewTypedListFromList[int, uint64](untyped)
+	suite.Require().Error(err)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTypedList(t *testing.T) {
+	suite.Run(t, new(TestTypedListSuite))
+}
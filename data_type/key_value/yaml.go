@@ -0,0 +1,342 @@
+package key_value
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxYAMLNodes limits the amount of the values that the aliases could expand into
+const maxYAMLNodes = 1_000_000
+
+// maxYAMLDepth is the maximum nesting of the mappings and sequences, including the expanded aliases
+const maxYAMLDepth = 1000
+
+// NewFromYAML decodes the YAML mapping into KeyValue.
+//
+// The values get the same types as in NewFromString, except the numbers:
+// the natural numbers and zero are uint64, the negative integers are int64,
+// the integers that don't fit into 64 bits are json.Number, and the fractions are float64.
+// The timestamps and the binaries are kept as the strings, the way json sees them.
+// The anchors, aliases and merge keys are resolved, and the alias inside its own anchor is rejected.
+// The nesting is limited to 1000 levels, including the expanded aliases.
+//
+// The null values are not allowed, same as in NewFromString.
+// The empty document returns the empty KeyValue.
+func NewFromYAML(data []byte) (KeyValue, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+	if document.Kind == 0 {
+		return New(), nil
+	}
+
+	decoder := &yamlDecoder{expanding: make(map[*yaml.Node]bool)}
+	value, err := decoder.value(&document)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+	decoded, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("top level value is %T, not a mapping", value)
+	}
+
+	return decoded, nil
+}
+
+// YAML serializes k into the YAML document.
+// The keys are sorted, and the numbers keep their kind:
+// the integers are written without the fraction, and the floats always have it.
+// The big numbers are written as the integers of any length.
+// The []byte is written as the !!binary base64 string.
+func (k KeyValue) YAML() ([]byte, error) {
+	value, err := binaryMap(k)
+	if err != nil {
+		return nil, fmt.Errorf("binaryMap: %w", err)
+	}
+
+	buffer := bytes.Buffer{}
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlNode(value)); err != nil {
+		return nil, fmt.Errorf("yaml.Encode: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("yaml.Close: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// yamlDecoder converts the yaml nodes into the values of KeyValue
+type yamlDecoder struct {
+	nodes     int
+	depth     int
+	expanding map[*yaml.Node]bool // the anchored nodes of the aliases being expanded
+}
+
+func (d *yamlDecoder) value(node *yaml.Node) (interface{}, error) {
+	d.nodes++
+	if d.nodes > maxYAMLNodes {
+		return nil, fmt.Errorf("more than %d values", maxYAMLNodes)
+	}
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return map[string]interface{}{}, nil
+		}
+		return d.value(node.Content[0])
+	case yaml.AliasNode:
+		if err := d.enterAlias(node); err != nil {
+			return nil, err
+		}
+		defer d.leaveAlias(node)
+		return d.value(node.Alias)
+	case yaml.SequenceNode:
+		list := make([]interface{}, len(node.Content))
+		for i, element := range node.Content {
+			value, err := d.value(element)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			list[i] = value
+		}
+		return list, nil
+	case yaml.MappingNode:
+		decoded := make(map[string]interface{}, len(node.Content)/2)
+		if err := d.mapping(node, decoded); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	case yaml.ScalarNode:
+		return yamlScalar(node)
+	}
+
+	return nil, fmt.Errorf("unsupported node kind %d at line %d", node.Kind, node.Line)
+}
+
+// enter increases the nesting depth
+func (d *yamlDecoder) enter() error {
+	d.depth++
+	if d.depth > maxYAMLDepth {
+		return fmt.Errorf("nesting exceeds %d levels: %w", maxYAMLDepth, ErrTooDeep)
+	}
+	return nil
+}
+
+func (d *yamlDecoder) leave() {
+	d.depth--
+}
+
+// enterAlias marks the anchored node of the alias as being expanded.
+// The alias that refers to the node which is being expanded is recursive, and it's rejected.
+func (d *yamlDecoder) enterAlias(node *yaml.Node) error {
+	if d.expanding[node.Alias] {
+		return fmt.Errorf("alias *%s at line %d refers to itself", node.Value, node.Line)
+	}
+	d.expanding[node.Alias] = true
+	return nil
+}
+
+func (d *yamlDecoder) leaveAlias(node *yaml.Node) {
+	delete(d.expanding, node.Alias)
+}
+
+// mapping sets the keys of the mapping node into decoded.
+// The keys set by the merge key "<<" don't override the explicit keys.
+func (d *yamlDecoder) mapping(node *yaml.Node, decoded map[string]interface{}) error {
+	merged := make([]*yaml.Node, 0)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Kind != yaml.ScalarNode {
+			return fmt.Errorf("key at line %d is not a scalar", keyNode.Line)
+		}
+		if keyNode.ShortTag() == "!!merge" {
+			merged = append(merged, valueNode)
+			continue
+		}
+
+		value, err := d.value(valueNode)
+		if err != nil {
+			return fmt.Errorf("%s: %w", keyNode.Value, err)
+		}
+		decoded[keyNode.Value] = value
+	}
+
+	for _, valueNode := range merged {
+		if err := d.merge(valueNode, decoded, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// merge sets the keys of the merge key value into decoded, unless they are set already.
+// The value is the mapping or, if it's not nested, the sequence of the mappings.
+func (d *yamlDecoder) merge(node *yaml.Node, decoded map[string]interface{}, nested bool) error {
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer d.leave()
+
+	if node.Kind == yaml.AliasNode {
+		if err := d.enterAlias(node); err != nil {
+			return fmt.Errorf("<<: %w", err)
+		}
+		defer d.leaveAlias(node)
+		node = node.Alias
+	}
+	if node.Kind == yaml.SequenceNode && !nested {
+		for _, source := range node.Content {
+			if err := d.merge(source, decoded, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("merge key at line %d is not a mapping", node.Line)
+	}
+
+	mergedKeys := make(map[string]interface{}, len(node.Content)/2)
+	if err := d.mapping(node, mergedKeys); err != nil {
+		return fmt.Errorf("<<: %w", err)
+	}
+	for key, value := range mergedKeys {
+		if _, ok := decoded[key]; !ok {
+			decoded[key] = value
+		}
+	}
+
+	return nil
+}
+
+// yamlScalar converts the scalar by its resolved tag
+func yamlScalar(node *yaml.Node) (interface{}, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return nil, fmt.Errorf("value at line %d is %w", node.Line, ErrNilValue)
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return nil, fmt.Errorf("bool at line %d: %w", node.Line, err)
+		}
+		return b, nil
+	case "!!int":
+		integer, ok := yamlInteger(node.Value)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer '%s' at line %d", node.Value, node.Line)
+		}
+		return integer, nil
+	case "!!float":
+		// yaml resolves the integers that don't fit into 64 bits as the floats
+		if node.Style&yaml.TaggedStyle == 0 {
+			if integer, ok := yamlInteger(node.Value); ok {
+				return integer, nil
+			}
+		}
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return nil, fmt.Errorf("float at line %d: %w", node.Line, err)
+		}
+		return f, nil
+	}
+
+	// the strings, timestamps, binaries and the custom tags
+	return node.Value, nil
+}
+
+// yamlInteger parses the integer as uint64, int64, or json.Number if it doesn't fit into 64 bits
+func yamlInteger(text string) (interface{}, bool) {
+	// the base 0 accepts the 0x, 0o and 0b prefixes and the underscores
+	number, ok := new(big.Int).SetString(strings.TrimPrefix(text, "+"), 0)
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case number.IsUint64():
+		return number.Uint64(), true
+	case number.IsInt64():
+		return number.Int64(), true
+	}
+	return json.Number(number.String()), true
+}
+
+// yamlNode returns the node of the value converted by binaryValue
+func yamlNode(value interface{}) *yaml.Node {
+	scalar := func(tag string, text string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: text}
+	}
+
+	switch typed := value.(type) {
+	case bool:
+		return scalar("!!bool", strconv.FormatBool(typed))
+	case uint64:
+		return scalar("!!int", strconv.FormatUint(typed, 10))
+	case int64:
+		return scalar("!!int", strconv.FormatInt(typed, 10))
+	case *big.Int:
+		return scalar("!!int", typed.String())
+	case float64:
+		return scalar("!!float", yamlFloat(typed))
+	case string:
+		return scalar("!!str", typed)
+	case []byte:
+		return scalar("!!binary", base64.StdEncoding.EncodeToString(typed))
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: make([]*yaml.Node, len(typed))}
+		for i, element := range typed {
+			node.Content[i] = yamlNode(element)
+		}
+		return node
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: make([]*yaml.Node, 0, 2*len(keys))}
+		for _, key := range keys {
+			node.Content = append(node.Content, scalar("!!str", key), yamlNode(typed[key]))
+		}
+		return node
+	}
+
+	// binaryValue returns only the types above
+	panic(fmt.Sprintf("unsupported type %T", value))
+}
+
+// yamlFloat formats the float, so it's not read back as the integer
+func yamlFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return ".nan"
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	}
+
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
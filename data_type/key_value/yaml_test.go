@@ -0,0 +1,179 @@
+package key_value
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestYAMLSuite struct {
+	suite.Suite
+}
+
+func (suite *TestYAMLSuite) TestNewFromYAML() {
+	kv, err := NewFromYAML([]byte(`
+name: sds
+amount: 5
+delta: -5
+price: 1.5
+hex: 0x1F
+paid: true
+quoted: "123"
+supply: 123456789012345678901234567890
+created: 2023-01-02T03:04:05Z
+tags: [a, b]
+order:
+  items:
+    - qty: 2
+    - qty: 3
+`))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal("sds", kv["name"])
+	suite.Require().Equal(uint64(5), kv["amount"])
+	suite.Require().Equal(int64(-5), kv["delta"])
+	suite.Require().Equal(1.5, kv["price"])
+	suite.Require().Equal(uint64(31), kv["hex"])
+	suite.Require().Equal(true, kv["paid"])
+	suite.Require().Equal("123", kv["quoted"])
+
+	supply, err := kv.BigIntValue("supply")
+	suite.Require().NoError(err)
+	suite.Require().Equal("123456789012345678901234567890", supply.String())
+
+	created, err := kv.TimeValue("created")
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(1672628645), created.Unix())
+
+	tags, err := kv.StringsValue("tags")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a", "b"}, tags)
+
+	qty, err := kv.Uint64At("order.items.1.qty")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(3), qty)
+
+	// same data as json
+	fromJson, err := NewFromString(`{"name":"sds","amount":5,"delta":-5,"price":1.5,"hex":31,"paid":true,
+		"quoted":"123","supply":123456789012345678901234567890,"created":"2023-01-02T03:04:05Z",
+		"tags":["a","b"],"order":{"items":[{"qty":2},{"qty":3}]}}`)
+	suite.Require().NoError(err)
+	suite.Require().True(kv.Equal(fromJson))
+
+	// the empty document
+	kv, err = NewFromYAML([]byte(""))
+	suite.Require().NoError(err)
+	suite.Require().Empty(kv)
+}
+
+func (suite *TestYAMLSuite) TestMerge() {
+	kv, err := NewFromYAML([]byte(`
+defaults: &defaults
+  timeout: 1s
+  retries: 3
+service:
+  <<: *defaults
+  retries: 5
+`))
+	suite.Require().NoError(err)
+
+	timeout, err := kv.DurationAt("service.timeout")
+	suite.Require().NoError(err)
+	suite.Require().Equal("1s", timeout.String())
+
+	// the explicit key is not overridden
+	retries, err := kv.Uint64At("service.retries")
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(5), retries)
+}
+
+func (suite *TestYAMLSuite) TestInvalid() {
+	_, err := NewFromYAML([]byte("name: ~"))
+	suite.Require().ErrorIs(err, ErrNilValue)
+	_, err = NewFromYAML([]byte("list: [1, null]"))
+	suite.Require().ErrorIs(err, ErrNilValue)
+	_, err = NewFromYAML([]byte("nested:\n  name:\n"))
+	suite.Require().ErrorIs(err, ErrNilValue)
+
+	// not a mapping
+	_, err = NewFromYAML([]byte("- a"))
+	suite.Require().Error(err)
+	_, err = NewFromYAML([]byte("? [a]\n: b"))
+	suite.Require().Error(err)
+	_, err = NewFromYAML([]byte("name: [a"))
+	suite.Require().Error(err)
+}
+
+func (suite *TestYAMLSuite) TestRecursiveAlias() {
+	_, err := NewFromYAML([]byte("a: &a [*a]\n"))
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "alias *a at line 1 refers to itself")
+
+	_, err = NewFromYAML([]byte("a: &a {b: *a}\n"))
+	suite.Require().Error(err)
+	_, err = NewFromYAML([]byte("a: &a {<<: *a}\n"))
+	suite.Require().Error(err)
+	_, err = NewFromYAML([]byte("a: &a {<<: [*a]}\n"))
+	suite.Require().Error(err)
+
+	// the same alias is used many times, if it's not inside itself
+	kv, err := NewFromYAML([]byte("a: &a [1]\nb: [*a, *a, [*a]]\nc: &c {d: *a}\ne: {<<: [*c, *c]}\n"))
+	suite.Require().NoError(err)
+	suite.Require().Equal([]interface{}{uint64(1)}, kv["a"])
+	suite.Require().Len(kv["b"], 3)
+	suite.Require().Equal(map[string]interface{}{"d": []interface{}{uint64(1)}}, kv["e"])
+
+	// too deep nesting
+	deep := "a: " + strings.Repeat("[", maxYAMLDepth) + strings.Repeat("]", maxYAMLDepth)
+	_, err = NewFromYAML([]byte(deep))
+	suite.Require().ErrorIs(err, ErrTooDeep)
+}
+
+func (suite *TestYAMLSuite) TestRoundTrip() {
+	kv := KeyValue{
+		"uint":    uint64(math.MaxUint64),
+		"int":     int64(-5),
+		"float":   float64(5),
+		"big":     new(big.Int).Lsh(big.NewInt(1), 100),
+		"number":  "123",
+		"bool":    true,
+		"strings": []string{"a", "true"},
+		"nested":  KeyValue{"items": []KeyValue{{"qty": 1}}},
+	}
+
+	encoded, err := kv.YAML()
+	suite.Require().NoError(err)
+	decoded, err := NewFromYAML(encoded)
+	suite.Require().NoError(err)
+
+	suite.Require().True(kv.Equal(decoded))
+	suite.Require().Equal(uint64(math.MaxUint64), decoded["uint"])
+	suite.Require().Equal(int64(-5), decoded["int"])
+	suite.Require().Equal(float64(5), decoded["float"])
+	suite.Require().Equal("123", decoded["number"])
+	suite.Require().Equal([]interface{}{"a", "true"}, decoded["strings"])
+
+	big, err := decoded.BigIntValue("big")
+	suite.Require().NoError(err)
+	suite.Require().Equal(kv["big"], big)
+
+	// the keys are sorted
+	again, err := KeyValue{"b": 1, "a": "x"}.YAML()
+	suite.Require().NoError(err)
+	suite.Require().Equal("a: x\nb: 1\n", string(again))
+
+	_, err = KeyValue{"nil": nil}.YAML()
+	suite.Require().ErrorIs(err, ErrNilValue)
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestYAML(t *testing.T) {
+	suite.Run(t, new(TestYAMLSuite))
+}
@@ -0,0 +1,206 @@
+package data_type
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// PriorityItem is the element in the PriorityQueue.
+// It's returned by the Push, and used as a handle to update or remove the element.
+type PriorityItem[T any] struct {
+	value    T
+	priority int
+	seq      uint64 // the order of addition, it keeps the elements with equal priority in FIFO order
+	index    int    // position in the heap, -1 if the element is not in the queue
+}
+
+// Value returns the element
+func (item *PriorityItem[T]) Value() T {
+	return item.value
+}
+
+// Priority returns the priority of the element
+func (item *PriorityItem[T]) Priority() int {
+	return item.priority
+}
+
+// priorityHeap implements the container/heap.Interface
+type priorityHeap[T any] struct {
+	items []*PriorityItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *priorityHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *priorityHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less != nil {
+		if h.less(a.value, b.value) {
+			return true
+		}
+		if h.less(b.value, a.value) {
+			return false
+		}
+	}
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.seq < b.seq
+}
+
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *priorityHeap[T]) Push(x any) {
+	item := x.(*PriorityItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *priorityHeap[T]) Pop() any {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items[last] = nil
+	h.items = h.items[:last]
+	item.index = -1
+	return item
+}
+
+// PriorityQueue is the queue where the element with the highest priority is taken first.
+// The elements with the same priority are taken in the order they were added.
+type PriorityQueue[T any] struct {
+	h   *priorityHeap[T]
+	cap uint
+	seq uint64
+}
+
+// NewPriorityQueue returns the queue of T elements that could contain
+// maximum QueueCap number of elements.
+// The elements are ordered by the priority passed to Push.
+func NewPriorityQueue[T any]() *PriorityQueue[T] {
+	return NewPriorityQueueFunc[T](nil)
+}
+
+// NewPriorityQueueFunc returns the queue of T elements that could contain
+// maximum QueueCap number of elements.
+// The elements are ordered by less, which returns true if a must be taken before b.
+// If neither of the elements is less, then the priority decides.
+func NewPriorityQueueFunc[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		h: &priorityHeap[T]{
+			items: make([]*PriorityItem[T], 0),
+			less:  less,
+		},
+		cap: QueueCap,
+	}
+}
+
+func (q *PriorityQueue[T]) Len() uint {
+	return uint(q.h.Len())
+}
+
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return q.h.Len() == 0
+}
+
+func (q *PriorityQueue[T]) IsFull() bool {
+	return uint(q.h.Len()) >= q.cap
+}
+
+// SetCap updates the queue size if it's possible.
+// If the new cap is less that current queue size throws an error.
+func (q *PriorityQueue[T]) SetCap(newCap uint) error {
+	if q.Len() > newCap {
+		return fmt.Errorf("trying to set %d as cap, however queue has %d elements", newCap, q.Len())
+	}
+
+	q.cap = newCap
+
+	return nil
+}
+
+// Cap returns the capacity of the queue
+func (q *PriorityQueue[T]) Cap() uint {
+	return q.cap
+}
+
+// Push the element with the given priority.
+// Returns the handle of the element that could be used in Update and Remove.
+// Returns an error if the queue is full.
+func (q *PriorityQueue[T]) Push(value T, priority int) (*PriorityItem[T], error) {
+	if q.IsFull() {
+		return nil, fmt.Errorf("queue is full, cap: %d", q.cap)
+	}
+
+	item := &PriorityItem[T]{
+		value:    value,
+		priority: priority,
+		seq:      q.seq,
+	}
+	q.seq++
+	heap.Push(q.h, item)
+
+	return item, nil
+}
+
+// Peek returns the element with the highest priority without removing it from the queue.
+// If there is no element, then returns false.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if q.IsEmpty() {
+		var empty T
+		return empty, false
+	}
+
+	return q.h.items[0].value, true
+}
+
+// Pop takes the element with the highest priority from the queue and returns it.
+// If there is no element, then returns false.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if q.IsEmpty() {
+		var empty T
+		return empty, false
+	}
+
+	item := heap.Pop(q.h).(*PriorityItem[T])
+	return item.value, true
+}
+
+// contains returns true if the item is in this queue
+func (q *PriorityQueue[T]) contains(item *PriorityItem[T]) bool {
+	return item != nil &&
+		item.index >= 0 &&
+		item.index < q.h.Len() &&
+		q.h.items[item.index] == item
+}
+
+// Update changes the value and priority of the element in the queue.
+// The element keeps its position among the elements with the same priority.
+func (q *PriorityQueue[T]) Update(item *PriorityItem[T], value T, priority int) error {
+	if !q.contains(item) {
+		return fmt.Errorf("the element not found")
+	}
+
+	item.value = value
+	item.priority = priority
+	heap.Fix(q.h, item.index)
+
+	return nil
+}
+
+// Remove the element from the queue and return its value.
+func (q *PriorityQueue[T]) Remove(item *PriorityItem[T]) (T, error) {
+	if !q.contains(item) {
+		var empty T
+		return empty, fmt.Errorf("the element not found")
+	}
+
+	heap.Remove(q.h, item.index)
+
+	return item.value, nil
+}
@@ -0,0 +1,131 @@
+package data_type
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestPriorityQueueSuite struct {
+	suite.Suite
+	queue *PriorityQueue[string]
+}
+
+// SetupTest prepares the empty queue.
+func (suite *TestPriorityQueueSuite) SetupTest() {
+	queue := NewPriorityQueue[string]()
+	suite.queue = queue
+
+	suite.Require().True(queue.IsEmpty())
+	suite.Require().False(queue.IsFull())
+	suite.Require().Zero(queue.Len())
+	suite.Require().Equal(QueueCap, queue.Cap())
+
+	_, ok := queue.Peek()
+	suite.Require().False(ok)
+}
+
+func (suite *TestPriorityQueueSuite) popAll(queue *PriorityQueue[string]) []string {
+	values := make([]string, 0, queue.Len())
+	for !queue.IsEmpty() {
+		value, ok := queue.Pop()
+		suite.Require().True(ok)
+		values = append(values, value)
+	}
+	return values
+}
+
+func (suite *TestPriorityQueueSuite) TestPriorityOrder() {
+	_, err := suite.queue.Push("bulk_1", 0)
+	suite.Require().NoError(err)
+	_, err = suite.queue.Push("urgent_1", 10)
+	suite.Require().NoError(err)
+	_, err = suite.queue.Push("bulk_2", 0)
+	suite.Require().NoError(err)
+	_, err = suite.queue.Push("normal", 5)
+	suite.Require().NoError(err)
+	_, err = suite.queue.Push("urgent_2", 10)
+	suite.Require().NoError(err)
+
+	first, ok := suite.queue.Peek()
+	suite.Require().True(ok)
+	suite.Require().Equal("urgent_1", first)
+
+	// the elements with equal priority are in FIFO order
+	expected := []string{"urgent_1", "urgent_2", "normal", "bulk_1", "bulk_2"}
+	suite.Require().Equal(expected, suite.popAll(suite.queue))
+
+	_, ok = suite.queue.Pop()
+	suite.Require().False(ok)
+}
+
+func (suite *TestPriorityQueueSuite) TestCap() {
+	suite.Require().NoError(suite.queue.SetCap(2))
+	_, err := suite.queue.Push("a", 0)
+	suite.Require().NoError(err)
+	_, err = suite.queue.Push("b", 0)
+	suite.Require().NoError(err)
+	suite.Require().True(suite.queue.IsFull())
+
+	_, err = suite.queue.Push("c", 100)
+	suite.Require().Error(err)
+	suite.Require().Error(suite.queue.SetCap(1))
+}
+
+func (suite *TestPriorityQueueSuite) TestUpdateRemove() {
+	a, err := suite.queue.Push("a", 1)
+	suite.Require().NoError(err)
+	b, err := suite.queue.Push("b", 2)
+	suite.Require().NoError(err)
+	c, err := suite.queue.Push("c", 3)
+	suite.Require().NoError(err)
+
+	// move a to the top
+	suite.Require().NoError(suite.queue.Update(a, "a+", 4))
+	suite.Require().Equal("a+", a.Value())
+	suite.Require().Equal(4, a.Priority())
+
+	value, err := suite.queue.Remove(c)
+	suite.Require().NoError(err)
+	suite.Require().Equal("c", value)
+
+	// removed elements can not be changed
+	suite.Require().Error(suite.queue.Update(c, "c", 0))
+	_, err = suite.queue.Remove(c)
+	suite.Require().Error(err)
+
+	// the element of another queue
+	other := NewPriorityQueue[string]()
+	d, err := other.Push("d", 0)
+	suite.Require().NoError(err)
+	suite.Require().Error(suite.queue.Update(d, "d", 0))
+	suite.Require().Error(suite.queue.Update(nil, "d", 0))
+
+	suite.Require().Equal([]string{"a+", "b"}, suite.popAll(suite.queue))
+	// popped elements are not in the queue anymore
+	_, err = suite.queue.Remove(b)
+	suite.Require().Error(err)
+}
+
+func (suite *TestPriorityQueueSuite) TestOrderingFunc() {
+	// shorter strings go first
+	queue := NewPriorityQueueFunc[string](func(a, b string) bool {
+		return len(a) < len(b)
+	})
+
+	for _, value := range []string{"ccc", "a", "bb", "x", "dddd"} {
+		_, err := queue.Push(value, 0)
+		suite.Require().NoError(err)
+	}
+
+	suite.Require().Equal([]string{"a", "x", "bb", "ccc", "dddd"}, suite.popAll(queue))
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestPriorityQueue(t *testing.T) {
+	suite.Run(t, new(TestPriorityQueueSuite))
+}
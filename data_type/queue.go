@@ -4,20 +4,26 @@
 //   - Queue is the list where the new element is added to the end,
 //     but when an element is taken its taken from the top.
 //     Queue doesn't allow addition of any kind of element. All elements should have the same type.
+//   - TypedQueue is the generic Queue, the element type is checked at compile time.
+//   - ConcurrentQueue is the TypedQueue that is safe to share between the goroutines.
+//   - PriorityQueue is the queue where the element with the highest priority is taken first.
+//   - DelayQueue is the queue where the element becomes visible at the scheduled time.
 //   - Key_value different kinds of maps
 //   - serialize functions to serialize any structure to the bytes and vice versa.
 package data_type
 
 import (
-	"container/list"
 	"fmt"
 	"reflect"
 )
 
+// Queue is the compatibility wrapper around TypedQueue[any].
+// It keeps the runtime type check of the elements.
+// For the new code use TypedQueue.
 type Queue struct {
-	l           *list.List
-	cap         uint
+	q           *TypedQueue[any]
 	elementType reflect.Type
+	mismatched  uint64
 }
 
 const QueueCap uint = 10
@@ -32,72 +38,93 @@ const QueueCap uint = 10
 func NewQueue() *Queue {
 	return &Queue{
 		elementType: nil,
-		cap:         QueueCap,
-		l:           list.New(),
+		q:           NewTypedQueue[any](),
+	}
+}
+
+// NewQueueWithPolicy returns the queue with the given cap.
+// The policy defines what happens when an element is pushed into the full queue.
+func NewQueueWithPolicy(cap uint, policy OverflowPolicy) *Queue {
+	return &Queue{
+		elementType: nil,
+		q:           NewTypedQueueWithPolicy[any](cap, policy),
 	}
 }
 
 func (q *Queue) Len() uint {
-	return uint(q.l.Len())
+	return q.q.Len()
 }
 
 func (q *Queue) IsEmpty() bool {
-	return q.l.Len() == 0
+	return q.q.IsEmpty()
 }
 
 func (q *Queue) IsFull() bool {
-	return uint(q.l.Len()) == q.cap
+	return q.q.IsFull()
 }
 
 // SetCap updates the queue size if it's possible.
 // If the new cap is less that current queue size throws an error.
 // Otherwise, queue will lose access to the items.
 func (q *Queue) SetCap(newCap uint) error {
-	if q.Len() > newCap {
-		return fmt.Errorf("trying to set %d as cap, however queue has %d elements", newCap, q.Len())
+	if err := q.q.SetCap(newCap); err != nil {
+		return fmt.Errorf("q.SetCap: %w", err)
 	}
 
-	q.cap = newCap
-
 	return nil
 }
 
 // Cap returns the capacity of the queue
 func (q *Queue) Cap() uint {
-	return q.cap
+	return q.q.Cap()
+}
+
+// Policy returns the overflow policy of the queue
+func (q *Queue) Policy() OverflowPolicy {
+	return q.q.Policy()
+}
+
+// Stats returns the amount of elements lost due to the overflow policy or the type mismatch
+func (q *Queue) Stats() QueueStats {
+	stats := q.q.Stats()
+	stats.Mismatched = q.mismatched
+
+	return stats
 }
 
 // Push the element into the queue.
 // If the element type is not the same as
 // the expected type, then
-// It will silently drop it.
-// Silently drop if the queue is full
+// It will silently drop it, and count it in Stats.
+// The type of the first added element is expected.
+// If the queue is full, then the overflow policy is applied.
+// By default, the element is rejected, and the rejection is counted in Stats.
 func (q *Queue) Push(item interface{}) {
-	if q.IsFull() {
+	if q.elementType != nil && reflect.TypeOf(item) != q.elementType {
+		q.mismatched++
+		return
+	}
+
+	before := q.q.Stats()
+	if err := q.q.Push(item); err != nil {
 		return
 	}
-	if q.elementType == nil {
+	// the type is locked only if the element is added
+	if q.elementType == nil && q.q.Stats().DroppedNewest == before.DroppedNewest {
 		q.elementType = reflect.TypeOf(item)
-		q.l.PushBack(item)
-	} else if reflect.TypeOf(item) == q.elementType {
-		q.l.PushBack(item)
 	}
 }
 
 // First returns the first element without removing it from the queue
 // If there is no element, then returns nil
 func (q *Queue) First() interface{} {
-	if q.IsEmpty() {
-		return nil
-	}
-	return q.l.Front().Value
+	item, _ := q.q.Peek()
+	return item
 }
 
 // Pop takes the first element from the list and returns it.
 // If there is no element in the list, then return nil
 func (q *Queue) Pop() interface{} {
-	if q.IsEmpty() {
-		return nil
-	}
-	return q.l.Remove(q.l.Front())
+	item, _ := q.q.Pop()
+	return item
 }
@@ -113,6 +113,47 @@ func (suite *TestQueueSuite) TestPushPull() {
 	suite.Require().True(suite.key.IsFull())
 }
 
+func (suite *TestQueueSuite) TestPolicy() {
+	// by default the overflow is silently dropped
+	suite.Require().Equal(RejectOverflow, suite.key.Policy())
+	for i := 0; i <= int(QueueCap); i++ {
+		suite.key.Push(i)
+	}
+	suite.Require().Equal(QueueCap, suite.key.Len())
+	suite.Require().Equal(QueueStats{Rejected: 1}, suite.key.Stats())
+
+	ring := NewQueueWithPolicy(2, DropOldest)
+	ring.Push(1)
+	ring.Push(2)
+	ring.Push(3)
+	suite.Require().EqualValues(2, ring.Len())
+	suite.Require().Equal(2, ring.Pop())
+	suite.Require().Equal(3, ring.Pop())
+	suite.Require().Equal(QueueStats{DroppedOldest: 1}, ring.Stats())
+
+	// the type mismatch is counted
+	ring.Push("string")
+	suite.Require().True(ring.IsEmpty())
+	suite.Require().Equal(QueueStats{DroppedOldest: 1, Mismatched: 1}, ring.Stats())
+}
+
+func (suite *TestQueueSuite) TestRejectedType() {
+	// the rejected element doesn't set the type of the queue
+	full := NewQueueWithPolicy(0, RejectOverflow)
+	full.Push("string")
+	suite.Require().NoError(full.SetCap(1))
+	full.Push(1)
+	suite.Require().Equal(1, full.First())
+	suite.Require().Equal(QueueStats{Rejected: 1}, full.Stats())
+
+	dropped := NewQueueWithPolicy(0, DropNewest)
+	dropped.Push("string")
+	suite.Require().NoError(dropped.SetCap(1))
+	dropped.Push(1)
+	suite.Require().Equal(1, dropped.First())
+	suite.Require().Equal(QueueStats{DroppedNewest: 1}, dropped.Stats())
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestQueue(t *testing.T) {
@@ -0,0 +1,153 @@
+package data_type
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// OverflowPolicy defines what the queue does when an element is pushed into the full queue.
+type OverflowPolicy uint8
+
+const (
+	// RejectOverflow returns an error, the element is not added.
+	RejectOverflow OverflowPolicy = iota
+	// DropOldest removes the first element to make a space for the new one.
+	// The queue acts as a ring buffer.
+	DropOldest
+	// DropNewest silently skips the pushed element.
+	DropNewest
+	// Grow ignores the cap, the queue grows without a limit.
+	Grow
+)
+
+// QueueStats counts the elements that the queue lost due to the overflow policy.
+type QueueStats struct {
+	Rejected      uint64 // Pushes returned an error by RejectOverflow
+	DroppedOldest uint64 // The first elements removed by DropOldest
+	DroppedNewest uint64 // Pushed elements skipped by DropNewest
+	Mismatched    uint64 // Pushed elements dropped by Queue, since their type differs from the first element
+}
+
+// TypedQueue is the generic version of the Queue.
+// The element type is checked by the compiler,
+// therefore, the queue doesn't need to compare the types at runtime.
+type TypedQueue[T any] struct {
+	l      *list.List
+	cap    uint
+	policy OverflowPolicy
+	stats  QueueStats
+}
+
+// NewTypedQueue returns the queue of T elements that could contain
+// maximum QueueCap number of elements.
+// The elements pushed into the full queue are rejected.
+func NewTypedQueue[T any]() *TypedQueue[T] {
+	return NewTypedQueueWithPolicy[T](QueueCap, RejectOverflow)
+}
+
+// NewTypedQueueWithPolicy returns the queue of T elements with the given cap.
+// The policy defines what happens when an element is pushed into the full queue.
+func NewTypedQueueWithPolicy[T any](cap uint, policy OverflowPolicy) *TypedQueue[T] {
+	return &TypedQueue[T]{
+		cap:    cap,
+		policy: policy,
+		l:      list.New(),
+	}
+}
+
+func (q *TypedQueue[T]) Len() uint {
+	return uint(q.l.Len())
+}
+
+func (q *TypedQueue[T]) IsEmpty() bool {
+	return q.l.Len() == 0
+}
+
+// IsFull returns true if the queue reached its cap.
+// The queue with Grow policy is never full.
+func (q *TypedQueue[T]) IsFull() bool {
+	if q.policy == Grow {
+		return false
+	}
+	return uint(q.l.Len()) >= q.cap
+}
+
+// SetCap updates the queue size if it's possible.
+// If the new cap is less that current queue size throws an error.
+func (q *TypedQueue[T]) SetCap(newCap uint) error {
+	if q.Len() > newCap {
+		return fmt.Errorf("trying to set %d as cap, however queue has %d elements", newCap, q.Len())
+	}
+
+	q.cap = newCap
+
+	return nil
+}
+
+// Cap returns the capacity of the queue
+func (q *TypedQueue[T]) Cap() uint {
+	return q.cap
+}
+
+// Policy returns the overflow policy of the queue
+func (q *TypedQueue[T]) Policy() OverflowPolicy {
+	return q.policy
+}
+
+// Stats returns the amount of elements lost due to the overflow policy
+func (q *TypedQueue[T]) Stats() QueueStats {
+	return q.stats
+}
+
+// Push the element to the end of the queue.
+// If the queue is full, the overflow policy is applied.
+// Returns an error only if the policy is RejectOverflow.
+func (q *TypedQueue[T]) Push(item T) error {
+	if q.IsFull() {
+		switch q.policy {
+		case DropNewest:
+			q.stats.DroppedNewest++
+			return nil
+		case DropOldest:
+			// nothing to remove in the queue without a cap
+			if q.cap == 0 {
+				q.stats.DroppedNewest++
+				return nil
+			}
+			q.l.Remove(q.l.Front())
+			q.stats.DroppedOldest++
+		default:
+			q.stats.Rejected++
+			return fmt.Errorf("queue is full, cap: %d", q.cap)
+		}
+	}
+
+	q.l.PushBack(item)
+
+	return nil
+}
+
+// Peek returns the first element without removing it from the queue.
+// If there is no element, then returns false.
+func (q *TypedQueue[T]) Peek() (T, bool) {
+	if q.IsEmpty() {
+		var empty T
+		return empty, false
+	}
+
+	// the comma-ok form keeps the nil elements of TypedQueue[any] from panicking
+	item, _ := q.l.Front().Value.(T)
+	return item, true
+}
+
+// Pop takes the first element from the queue and returns it.
+// If there is no element, then returns false.
+func (q *TypedQueue[T]) Pop() (T, bool) {
+	if q.IsEmpty() {
+		var empty T
+		return empty, false
+	}
+
+	item, _ := q.l.Remove(q.l.Front()).(T)
+	return item, true
+}
@@ -0,0 +1,142 @@
+package data_type
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Define the suite, and absorb the built-in basic suite
+// functionality from testify - including a T() method which
+// returns the current testing context
+type TestTypedQueueSuite struct {
+	suite.Suite
+	queue *TypedQueue[uint64]
+}
+
+// SetupTest prepares the empty queue.
+func (suite *TestTypedQueueSuite) SetupTest() {
+	queue := NewTypedQueue[uint64]()
+	suite.queue = queue
+
+	suite.Require().True(queue.IsEmpty())
+	suite.Require().False(queue.IsFull())
+	suite.Require().Zero(queue.Len())
+	suite.Require().Equal(QueueCap, queue.Cap())
+
+	_, ok := queue.Peek()
+	suite.Require().False(ok)
+}
+
+func (suite *TestTypedQueueSuite) TestPushPop() {
+	for i := uint64(0); i < uint64(QueueCap); i++ {
+		suite.Require().NoError(suite.queue.Push(i))
+		suite.Require().EqualValues(i+1, suite.queue.Len())
+	}
+	suite.Require().True(suite.queue.IsFull())
+
+	// the overflow is not silent
+	suite.Require().Error(suite.queue.Push(100))
+	suite.Require().Equal(QueueCap, suite.queue.Len())
+
+	// first in, first out
+	for i := uint64(0); i < uint64(QueueCap); i++ {
+		first, ok := suite.queue.Peek()
+		suite.Require().True(ok)
+		suite.Require().Equal(i, first)
+
+		item, ok := suite.queue.Pop()
+		suite.Require().True(ok)
+		suite.Require().Equal(i, item)
+	}
+
+	item, ok := suite.queue.Pop()
+	suite.Require().False(ok)
+	suite.Require().Zero(item)
+	suite.Require().True(suite.queue.IsEmpty())
+}
+
+func (suite *TestTypedQueueSuite) TestSetCap() {
+	suite.Require().NoError(suite.queue.Push(1))
+	suite.Require().NoError(suite.queue.Push(2))
+
+	// can not be less than the amount of elements
+	suite.Require().Error(suite.queue.SetCap(1))
+
+	suite.Require().NoError(suite.queue.SetCap(2))
+	suite.Require().True(suite.queue.IsFull())
+	suite.Require().Error(suite.queue.Push(3))
+
+	suite.Require().NoError(suite.queue.SetCap(3))
+	suite.Require().NoError(suite.queue.Push(3))
+}
+
+func (suite *TestTypedQueueSuite) TestAnyNil() {
+	// the wrapped queue may have nil elements
+	queue := NewTypedQueue[any]()
+	suite.Require().NoError(queue.Push(nil))
+
+	item, ok := queue.Pop()
+	suite.Require().True(ok)
+	suite.Require().Nil(item)
+}
+
+func (suite *TestTypedQueueSuite) TestRejectOverflow() {
+	suite.Require().Equal(RejectOverflow, suite.queue.Policy())
+	for i := uint64(0); i < uint64(QueueCap); i++ {
+		suite.Require().NoError(suite.queue.Push(i))
+	}
+
+	suite.Require().Error(suite.queue.Push(100))
+	suite.Require().Error(suite.queue.Push(101))
+	suite.Require().Equal(QueueStats{Rejected: 2}, suite.queue.Stats())
+}
+
+func (suite *TestTypedQueueSuite) TestDropOldest() {
+	queue := NewTypedQueueWithPolicy[int](3, DropOldest)
+	for i := 0; i < 5; i++ {
+		suite.Require().NoError(queue.Push(i))
+	}
+	suite.Require().True(queue.IsFull())
+	suite.Require().EqualValues(3, queue.Len())
+	suite.Require().Equal(QueueStats{DroppedOldest: 2}, queue.Stats())
+
+	// the latest elements are kept
+	for i := 2; i < 5; i++ {
+		item, ok := queue.Pop()
+		suite.Require().True(ok)
+		suite.Require().Equal(i, item)
+	}
+}
+
+func (suite *TestTypedQueueSuite) TestDropNewest() {
+	queue := NewTypedQueueWithPolicy[int](3, DropNewest)
+	for i := 0; i < 5; i++ {
+		suite.Require().NoError(queue.Push(i))
+	}
+	suite.Require().EqualValues(3, queue.Len())
+	suite.Require().Equal(QueueStats{DroppedNewest: 2}, queue.Stats())
+
+	// the earliest elements are kept
+	for i := 0; i < 3; i++ {
+		item, ok := queue.Pop()
+		suite.Require().True(ok)
+		suite.Require().Equal(i, item)
+	}
+}
+
+func (suite *TestTypedQueueSuite) TestGrow() {
+	queue := NewTypedQueueWithPolicy[int](3, Grow)
+	for i := 0; i < 5; i++ {
+		suite.Require().NoError(queue.Push(i))
+		suite.Require().False(queue.IsFull())
+	}
+	suite.Require().EqualValues(5, queue.Len())
+	suite.Require().Equal(QueueStats{}, queue.Stats())
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTypedQueue(t *testing.T) {
+	suite.Run(t, new(TestTypedQueueSuite))
+}
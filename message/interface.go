@@ -36,6 +36,18 @@ type RequestInterface interface {
 	RouteParameters() key_value.KeyValue
 }
 
+// Redactable is the message that hides its sensitive parameters in the logs.
+// The built-in messages implement it, check the others by the type assertion:
+//
+//	if redactable, ok := msg.(message.Redactable); ok {
+//		log.Println(redactable.Redacted())
+//	}
+type Redactable interface {
+	// Redacted is String with the sensitive parameters hidden by key_value.DefaultRedactor.
+	// Use it to log the message.
+	Redacted() string
+}
+
 type ReplyInterface interface {
 	ConId() string
 	SetConId(string)
@@ -288,6 +288,21 @@ func (request *RawRequest) String() string {
 	return JoinMessages(messages[contentOffset:contentEnd])
 }
 
+// Redacted returns the message as the default Request with the redacted parameters.
+// If the message is not the default Request, then it can not be redacted, and an empty string is returned.
+func (request *RawRequest) Redacted() string {
+	defReq, err := NewReq(request.messages)
+	if err != nil {
+		return ""
+	}
+	redactable, ok := defReq.(Redactable)
+	if !ok {
+		return ""
+	}
+
+	return redactable.Redacted()
+}
+
 // Bytes convert the message to the sequence of bytes
 func (request *RawRequest) Bytes() ([]byte, error) {
 	str := request.String()
@@ -443,6 +458,21 @@ func (reply *RawReply) String() string {
 	return JoinMessages(messages[contentOffset:contentEnd])
 }
 
+// Redacted returns the message as the default Reply with the redacted parameters.
+// If the message is not the default Reply, then it can not be redacted, and an empty string is returned.
+func (reply *RawReply) Redacted() string {
+	defRep, err := NewRep(reply.messages)
+	if err != nil {
+		return ""
+	}
+	redactable, ok := defRep.(Redactable)
+	if !ok {
+		return ""
+	}
+
+	return redactable.Redacted()
+}
+
 // ZmqEnvelope the message
 func (reply *RawReply) ZmqEnvelope() ([]string, error) {
 	preOffset := 1
@@ -528,6 +528,39 @@ func (test *TestRawSuite) Test_25_ReqToReply() {
 	s().Equal(value, replyValue)
 }
 
+// Test_26_Redacted tests that the sensitive parameters are hidden
+func (test *TestRawSuite) Test_26_Redacted() {
+	s := test.Require
+
+	req := &Request{
+		Command:    test.cmdName,
+		Parameters: key_value.New().Set(test.reqKey, 12).Set("password", "secret_value"),
+	}
+	reqStrings, err := req.ZmqEnvelope()
+	s().NoError(err)
+
+	rawReqInterface, err := NewRawReq(reqStrings)
+	s().NoError(err)
+	s().Implements((*Redactable)(nil), req)
+	s().Implements((*Redactable)(nil), &Reply{})
+	rawReq, ok := rawReqInterface.(*RawRequest)
+	s().True(ok)
+	s().Contains(rawReq.String(), "secret_value")
+	s().NotContains(rawReq.Redacted(), "secret_value")
+	s().Contains(rawReq.Redacted(), key_value.RedactedValue)
+
+	rawReply, ok := rawReq.Ok(key_value.New().Set("access_token", "secret_value")).(Redactable)
+	s().True(ok)
+	s().Contains(rawReply.(*RawReply).String(), "secret_value")
+	s().NotContains(rawReply.Redacted(), "secret_value")
+	s().Contains(rawReply.Redacted(), key_value.RedactedValue)
+
+	// the raw content is not the default message
+	rawReqInterface, err = NewRawReq(test.rawReq)
+	s().NoError(err)
+	s().Empty(rawReqInterface.(Redactable).Redacted())
+}
+
 // TestRaw tests the RawRequest and RawReply methods.
 //
 // The RawReply is identical to the RawRequest.
@@ -23,11 +23,17 @@ func NewEmptyReply() ReplyInterface {
 }
 
 // NewRep decodes Zeromq messages into Reply.
+// The messages are limited by key_value.DefaultDecodeOptions.
 func NewRep(messages []string) (ReplyInterface, error) {
+	return NewRepWithOptions(messages, key_value.DefaultDecodeOptions())
+}
+
+// NewRepWithOptions decodes Zeromq messages that don't exceed the options into Reply.
+func NewRepWithOptions(messages []string, options key_value.DecodeOptions) (ReplyInterface, error) {
 	msg := JoinMessages(messages)
-	data, err := key_value.NewFromString(msg)
+	data, err := key_value.NewFromStringWithOptions(msg, options)
 	if err != nil {
-		return nil, fmt.Errorf("key_value.NewFromString: %w", err)
+		return nil, fmt.Errorf("key_value.NewFromStringWithOptions: %w", err)
 	}
 
 	var reply Reply
@@ -94,6 +100,14 @@ func (reply *Reply) String() string {
 	return string(bytes)
 }
 
+// Redacted returns the String of the reply, where the parameters are redacted by key_value.DefaultRedactor
+func (reply *Reply) Redacted() string {
+	redacted := *reply
+	redacted.Parameters = key_value.DefaultRedactor.Redact(reply.Parameters)
+
+	return redacted.String()
+}
+
 func (reply *Reply) ZmqEnvelope() ([]string, error) {
 	bytes, err := reply.Bytes()
 	if err != nil {
@@ -133,6 +133,34 @@ func (suite *TestReplySuite) TestParsing() {
 	suite.NoError(err)
 }
 
+func (suite *TestReplySuite) TestRedacted() {
+	suite.ok.Parameters.Set("access_token", "secret_value").Set("amount", 5)
+
+	redacted := suite.ok.Redacted()
+	suite.Require().NotContains(redacted, "secret_value")
+	suite.Require().Contains(redacted, `"amount":5`)
+	suite.Require().Contains(suite.ok.String(), "secret_value")
+
+	suite.Require().Equal(suite.fail.String(), suite.fail.Redacted())
+}
+
+func (suite *TestReplySuite) TestOptions() {
+	options := key_value.DecodeOptions{MaxKeys: 3, MaxStringLen: 10}
+
+	_, err := NewRepWithOptions([]string{`{"message":"","parameters":{},"status":"OK"}`}, options)
+	suite.Require().NoError(err)
+
+	_, err = NewRepWithOptions([]string{`{"message":"","parameters":{},"status":"OK","sig":""}`}, options)
+	suite.Require().ErrorIs(err, key_value.ErrTooManyKeys)
+
+	_, err = NewRepWithOptions([]string{`{"message":"is too long","parameters":{},"status":"fail"}`}, options)
+	suite.Require().ErrorIs(err, key_value.ErrStringTooLong)
+
+	ops := DefaultMessageWithOptions(options)
+	_, err = ops.NewReply([]string{`{"message":"is too long","parameters":{},"status":"fail"}`})
+	suite.Require().ErrorIs(err, key_value.ErrStringTooLong)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestReply(t *testing.T) {
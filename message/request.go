@@ -29,17 +29,39 @@ func DefaultMessage() *Operations {
 	}
 }
 
+// DefaultMessageWithOptions returns the default message operations
+// that decode the messages with the given limits.
+func DefaultMessageWithOptions(options key_value.DecodeOptions) *Operations {
+	return &Operations{
+		Name: "default",
+		NewReq: func(messages []string) (RequestInterface, error) {
+			return NewReqWithOptions(messages, options)
+		},
+		NewReply: func(messages []string) (ReplyInterface, error) {
+			return NewRepWithOptions(messages, options)
+		},
+		EmptyReq:   NewEmptyReq,
+		EmptyReply: NewEmptyReply,
+	}
+}
+
 func NewEmptyReq() RequestInterface {
 	return &Request{}
 }
 
-// NewReq from the zeromq messages
+// NewReq from the zeromq messages.
+// The messages are limited by key_value.DefaultDecodeOptions.
 func NewReq(messages []string) (RequestInterface, error) {
+	return NewReqWithOptions(messages, key_value.DefaultDecodeOptions())
+}
+
+// NewReqWithOptions from the zeromq messages that don't exceed the options
+func NewReqWithOptions(messages []string, options key_value.DecodeOptions) (RequestInterface, error) {
 	msg := JoinMessages(messages)
 
-	data, err := key_value.NewFromString(msg)
+	data, err := key_value.NewFromStringWithOptions(msg, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert message string %s to key-value: %v", msg, err)
+		return nil, fmt.Errorf("failed to convert message string %s to key-value: %w", msg, err)
 	}
 
 	var request Request
@@ -164,6 +186,14 @@ func (request *Request) String() string {
 	return string(bytes)
 }
 
+// Redacted returns the String of the request, where the parameters are redacted by key_value.DefaultRedactor
+func (request *Request) Redacted() string {
+	redacted := *request
+	redacted.Parameters = key_value.DefaultRedactor.Redact(request.Parameters)
+
+	return redacted.String()
+}
+
 func (request *Request) ZmqEnvelope() ([]string, error) {
 	bytes, err := request.Bytes()
 	if err != nil {
@@ -184,9 +214,10 @@ func (request *Request) SetUuid() {
 }
 
 // Next creates a new request based on the previous one.
+// The parameters are copied, so the caller could keep changing them.
 func (request *Request) Next(command string, parameters key_value.KeyValue) {
 	request.Command = command
-	request.Parameters = parameters
+	request.Parameters = parameters.Clone()
 }
 
 // Fail creates a new Reply as a failure
@@ -204,11 +235,13 @@ func (request *Request) Fail(message string) ReplyInterface {
 	return reply
 }
 
+// Ok creates a new Reply as a success.
+// The parameters are copied, so the reply doesn't share them with the request.
 func (request *Request) Ok(parameters key_value.KeyValue) ReplyInterface {
 	reply := &Reply{
 		Status:     OK,
 		Message:    "",
-		Parameters: parameters,
+		Parameters: parameters.Clone(),
 		Trace:      request.Trace,
 		Uuid:       request.Uuid,
 		conId:      request.conId,
@@ -2,6 +2,7 @@ package message
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/ahmetson/datatype-lib/data_type/key_value"
@@ -113,6 +114,62 @@ func (suite *TestRequestSuite) TestParsing() {
 	suite.NoError(err)
 }
 
+func (suite *TestRequestSuite) TestNextAndOk() {
+	parameters := key_value.New().Set("nested", key_value.New().Set("key", "value"))
+
+	suite.ok.Next("next_command", parameters)
+	reply := suite.ok.Ok(suite.ok.Parameters)
+
+	// neither the request, nor the reply share the parameters
+	nested, err := parameters.NestedValue("nested")
+	suite.Require().NoError(err)
+	nested.Set("key", "changed")
+	reply.ReplyParameters().Set("reply_key", "reply_value")
+
+	value, err := suite.ok.Parameters.StringAt("nested.key")
+	suite.Require().NoError(err)
+	suite.Require().Equal("value", value)
+	suite.Require().False(suite.ok.Parameters.Exist("reply_key"))
+	suite.Require().Equal("next_command", suite.ok.Command)
+}
+
+func (suite *TestRequestSuite) TestRedacted() {
+	suite.ok.Parameters.Set("user", key_value.New().Set("name", "a").Set("password", "secret_value"))
+
+	redacted := suite.ok.Redacted()
+	suite.Require().NotContains(redacted, "secret_value")
+	suite.Require().Contains(redacted, key_value.RedactedValue)
+	suite.Require().Contains(redacted, `"name":"a"`)
+
+	// the request is not changed
+	password, err := suite.ok.Parameters.StringAt("user.password")
+	suite.Require().NoError(err)
+	suite.Require().Equal("secret_value", password)
+	suite.Require().Contains(suite.ok.String(), "secret_value")
+}
+
+func (suite *TestRequestSuite) TestOptions() {
+	options := key_value.DecodeOptions{MaxDepth: 2, DisallowDuplicateKeys: true}
+
+	_, err := NewReqWithOptions([]string{`{"command":"command","parameters":{}}`}, options)
+	suite.Require().NoError(err)
+
+	_, err = NewReqWithOptions([]string{`{"command":"command","parameters":{"nested":{}}}`}, options)
+	suite.Require().ErrorIs(err, key_value.ErrTooDeep)
+
+	_, err = NewReqWithOptions([]string{`{"command":"a","command":"b","parameters":{}}`}, options)
+	suite.Require().ErrorIs(err, key_value.ErrDuplicateKey)
+
+	// the default limits stop the abusive messages
+	large := `{"command":"command","parameters":{"number":1` + strings.Repeat("0", 1000) + `}}`
+	_, err = NewReq([]string{large})
+	suite.Require().ErrorIs(err, key_value.ErrTooManyDigits)
+
+	ops := DefaultMessageWithOptions(options)
+	_, err = ops.NewReq([]string{`{"command":"command","parameters":{"nested":{}}}`})
+	suite.Require().ErrorIs(err, key_value.ErrTooDeep)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestRequest(t *testing.T) {